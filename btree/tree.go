@@ -0,0 +1,385 @@
+package btree
+
+import (
+	"cmp"
+	"fmt"
+	"sort"
+
+	"github.com/fealsamh/datastructures/constraints"
+)
+
+type node[K any, V any] struct {
+	keys     []K
+	values   []V
+	children []*node[K, V]
+}
+
+func (n *node[K, V]) leaf() bool {
+	return len(n.children) == 0
+}
+
+// Tree is a generic B-tree.
+type Tree[K any, V any] struct {
+	root    *node[K, V]
+	compare func(K, K) int
+	// every node but the root holds between degree-1 and 2*degree-1 keys
+	degree int
+	size   int
+}
+
+// New creates a new B-tree with the given minimum degree.
+func New[K constraints.Comparable[K], V any](degree int) *Tree[K, V] {
+	return NewFunc[K, V](func(k1, k2 K) int { return k1.Compare(k2) }, degree)
+}
+
+// NewOrdered creates a new B-tree with the given minimum degree whose keys are ordered by the built-in ordering.
+func NewOrdered[K cmp.Ordered, V any](degree int) *Tree[K, V] {
+	return NewFunc[K, V](cmp.Compare[K], degree)
+}
+
+// NewFunc creates a new B-tree with the given minimum degree whose keys are ordered by a comparison function.
+// It panics if the degree is less than 2.
+func NewFunc[K any, V any](compare func(K, K) int, degree int) *Tree[K, V] {
+	if degree < 2 {
+		panic(fmt.Sprintf("bad B-tree degree %d", degree))
+	}
+	return &Tree[K, V]{compare: compare, degree: degree}
+}
+
+// Depth returns the depth of the tree.
+func (t *Tree[K, V]) Depth() int {
+	var d int
+	for n := t.root; n != nil; d++ {
+		if n.leaf() {
+			return d + 1
+		}
+		n = n.children[0]
+	}
+	return d
+}
+
+// Size returns the size of the tree.
+func (t *Tree[K, V]) Size() int {
+	return t.size
+}
+
+// Keys returns the keys of the items in the tree.
+func (t *Tree[K, V]) Keys() []K {
+	if t.root == nil {
+		return nil
+	}
+	ks := make([]K, 0, t.size)
+	t.Enumerate(func(k K, _ V) bool {
+		ks = append(ks, k)
+		return true
+	})
+	return ks
+}
+
+// Enumerate enumerates all the items in the tree.
+func (t *Tree[K, V]) Enumerate(f func(K, V) bool) bool {
+	if t.root == nil {
+		return true
+	}
+	return t.root.enumerate(f)
+}
+
+func (n *node[K, V]) enumerate(f func(K, V) bool) bool {
+	for i, k := range n.keys {
+		if !n.leaf() && !n.children[i].enumerate(f) {
+			return false
+		}
+		if !f(k, n.values[i]) {
+			return false
+		}
+	}
+	if !n.leaf() {
+		return n.children[len(n.keys)].enumerate(f)
+	}
+	return true
+}
+
+// Range enumerates the items whose keys lie between `lo` and `hi` in ascending order.
+// The flags determine whether the bounds themselves are included.
+func (t *Tree[K, V]) Range(lo, hi K, loInclusive, hiInclusive bool, f func(K, V) bool) bool {
+	if t.root == nil {
+		return true
+	}
+	_, ok := t.root.enumerateRange(t.compare, lo, hi, loInclusive, hiInclusive, f)
+	return ok
+}
+
+// enumerateRange returns false as its first result once a key beyond `hi` is reached.
+func (n *node[K, V]) enumerateRange(compare func(K, K) int, lo, hi K, loInclusive, hiInclusive bool, f func(K, V) bool) (bool, bool) {
+	i := sort.Search(len(n.keys), func(i int) bool {
+		c := compare(n.keys[i], lo)
+		return c > 0 || c == 0 && loInclusive
+	})
+	for ; i <= len(n.keys); i++ {
+		if !n.leaf() {
+			if more, ok := n.children[i].enumerateRange(compare, lo, hi, loInclusive, hiInclusive, f); !more || !ok {
+				return more, ok
+			}
+		}
+		if i == len(n.keys) {
+			break
+		}
+		if c := compare(n.keys[i], hi); c > 0 || c == 0 && !hiInclusive {
+			return false, true
+		}
+		if !f(n.keys[i], n.values[i]) {
+			return false, false
+		}
+	}
+	return true, true
+}
+
+// MinKey returns the minimum key in the tree or nil if the tree is empty.
+func (t *Tree[K, V]) MinKey() *K {
+	if t.root == nil {
+		return nil
+	}
+	n := t.root
+	for !n.leaf() {
+		n = n.children[0]
+	}
+	return &n.keys[0]
+}
+
+// MaxKey returns the maximum key in the tree or nil if the tree is empty.
+func (t *Tree[K, V]) MaxKey() *K {
+	if t.root == nil {
+		return nil
+	}
+	n := t.root
+	for !n.leaf() {
+		n = n.children[len(n.children)-1]
+	}
+	return &n.keys[len(n.keys)-1]
+}
+
+// search returns the position of the first key not less than the given one and whether it's equal to it.
+func (n *node[K, V]) search(compare func(K, K) int, key K) (int, bool) {
+	i := sort.Search(len(n.keys), func(i int) bool { return compare(n.keys[i], key) >= 0 })
+	return i, i < len(n.keys) && compare(n.keys[i], key) == 0
+}
+
+// Get returns the value for the given key.
+func (t *Tree[K, V]) Get(key K) (retValue V, found bool) {
+	for n := t.root; n != nil; {
+		i, ok := n.search(t.compare, key)
+		if ok {
+			return n.values[i], true
+		}
+		if n.leaf() {
+			break
+		}
+		n = n.children[i]
+	}
+	return
+}
+
+// Put inserts a new key-value pair into the tree or replaces the value for an existing key.
+func (t *Tree[K, V]) Put(key K, value V) (oldValue V, updated bool) {
+	return t.put(key, func() V { return value }, true)
+}
+
+// GetElsePut returns the value for the given key or inserts a new key-value pair into the tree.
+func (t *Tree[K, V]) GetElsePut(key K, fnValue func() V) (retValue V, updated bool) {
+	return t.put(key, fnValue, false)
+}
+
+func (t *Tree[K, V]) put(key K, fnValue func() V, replace bool) (V, bool) {
+	if t.root == nil {
+		t.root = &node[K, V]{}
+	}
+	if len(t.root.keys) == 2*t.degree-1 {
+		t.root = &node[K, V]{children: []*node[K, V]{t.root}}
+		t.root.splitChild(0)
+	}
+	n := t.root
+	for {
+		i, ok := n.search(t.compare, key)
+		if ok {
+			old := n.values[i]
+			if replace {
+				n.values[i] = fnValue()
+			}
+			return old, true
+		}
+		if n.leaf() {
+			value := fnValue()
+			n.keys = insertAt(n.keys, i, key)
+			n.values = insertAt(n.values, i, value)
+			t.size++
+			if replace {
+				var zero V
+				return zero, false
+			}
+			return value, false
+		}
+		if len(n.children[i].keys) == 2*t.degree-1 {
+			n.splitChild(i)
+			if c := t.compare(key, n.keys[i]); c == 0 {
+				continue
+			} else if c > 0 {
+				i++
+			}
+		}
+		n = n.children[i]
+	}
+}
+
+// splitChild splits the full i-th child of the node, moving its median item up.
+func (n *node[K, V]) splitChild(i int) {
+	c := n.children[i]
+	m := len(c.keys) / 2
+	r := &node[K, V]{
+		keys:   append([]K(nil), c.keys[m+1:]...),
+		values: append([]V(nil), c.values[m+1:]...),
+	}
+	if !c.leaf() {
+		r.children = append([]*node[K, V](nil), c.children[m+1:]...)
+		clear(c.children[m+1:])
+		c.children = c.children[:m+1]
+	}
+	n.keys = insertAt(n.keys, i, c.keys[m])
+	n.values = insertAt(n.values, i, c.values[m])
+	n.children = insertAt(n.children, i+1, r)
+	clear(c.keys[m:])
+	clear(c.values[m:])
+	c.keys, c.values = c.keys[:m], c.values[:m]
+}
+
+// Delete removes the key from the tree and returns its former value.
+func (t *Tree[K, V]) Delete(key K) (oldValue V, deleted bool) {
+	if t.root == nil {
+		return
+	}
+	oldValue, deleted = t.delete(t.root, key)
+	if len(t.root.keys) == 0 {
+		if t.root.leaf() {
+			t.root = nil
+		} else {
+			t.root = t.root.children[0]
+		}
+	}
+	if deleted {
+		t.size--
+	}
+	return
+}
+
+func (t *Tree[K, V]) delete(n *node[K, V], key K) (V, bool) {
+	for {
+		i, ok := n.search(t.compare, key)
+		if n.leaf() {
+			if !ok {
+				var zero V
+				return zero, false
+			}
+			old := n.values[i]
+			n.keys = removeAt(n.keys, i)
+			n.values = removeAt(n.values, i)
+			return old, true
+		}
+		if ok {
+			old := n.values[i]
+			switch l, r := n.children[i], n.children[i+1]; {
+			case len(l.keys) >= t.degree:
+				p := l.maxNode()
+				pk, pv := p.keys[len(p.keys)-1], p.values[len(p.values)-1]
+				n.keys[i], n.values[i] = pk, pv
+				t.delete(l, pk)
+			case len(r.keys) >= t.degree:
+				s := r.minNode()
+				sk, sv := s.keys[0], s.values[0]
+				n.keys[i], n.values[i] = sk, sv
+				t.delete(r, sk)
+			default:
+				n.merge(i)
+				t.delete(l, key)
+			}
+			return old, true
+		}
+		// the child descended into must hold at least `degree` keys
+		if len(n.children[i].keys) < t.degree {
+			i = t.fill(n, i)
+		}
+		n = n.children[i]
+	}
+}
+
+// fill ensures that the i-th child of the node holds at least `degree` keys and returns its new index.
+func (t *Tree[K, V]) fill(n *node[K, V], i int) int {
+	switch {
+	case i > 0 && len(n.children[i-1].keys) >= t.degree:
+		c, l := n.children[i], n.children[i-1]
+		c.keys = insertAt(c.keys, 0, n.keys[i-1])
+		c.values = insertAt(c.values, 0, n.values[i-1])
+		last := len(l.keys) - 1
+		n.keys[i-1], n.values[i-1] = l.keys[last], l.values[last]
+		l.keys, l.values = removeAt(l.keys, last), removeAt(l.values, last)
+		if !l.leaf() {
+			c.children = insertAt(c.children, 0, l.children[len(l.children)-1])
+			l.children = removeAt(l.children, len(l.children)-1)
+		}
+		return i
+	case i < len(n.keys) && len(n.children[i+1].keys) >= t.degree:
+		c, r := n.children[i], n.children[i+1]
+		c.keys = append(c.keys, n.keys[i])
+		c.values = append(c.values, n.values[i])
+		n.keys[i], n.values[i] = r.keys[0], r.values[0]
+		r.keys, r.values = removeAt(r.keys, 0), removeAt(r.values, 0)
+		if !r.leaf() {
+			c.children = append(c.children, r.children[0])
+			r.children = removeAt(r.children, 0)
+		}
+		return i
+	case i < len(n.keys):
+		n.merge(i)
+		return i
+	}
+	n.merge(i - 1)
+	return i - 1
+}
+
+// merge merges the i-th and (i+1)-th children of the node together with the i-th key.
+func (n *node[K, V]) merge(i int) {
+	l, r := n.children[i], n.children[i+1]
+	l.keys = append(append(l.keys, n.keys[i]), r.keys...)
+	l.values = append(append(l.values, n.values[i]), r.values...)
+	l.children = append(l.children, r.children...)
+	n.keys = removeAt(n.keys, i)
+	n.values = removeAt(n.values, i)
+	n.children = removeAt(n.children, i+1)
+}
+
+func (n *node[K, V]) minNode() *node[K, V] {
+	for !n.leaf() {
+		n = n.children[0]
+	}
+	return n
+}
+
+func (n *node[K, V]) maxNode() *node[K, V] {
+	for !n.leaf() {
+		n = n.children[len(n.children)-1]
+	}
+	return n
+}
+
+func insertAt[T any](s []T, i int, v T) []T {
+	var zero T
+	s = append(s, zero)
+	copy(s[i+1:], s[i:])
+	s[i] = v
+	return s
+}
+
+func removeAt[T any](s []T, i int) []T {
+	copy(s[i:], s[i+1:])
+	var zero T
+	s[len(s)-1] = zero
+	return s[:len(s)-1]
+}
@@ -0,0 +1,182 @@
+package btree
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/fealsamh/datastructures/constraints"
+	"github.com/stretchr/testify/assert"
+)
+
+type compString string
+
+func (s1 compString) Compare(s2 compString) int {
+	return strings.Compare(string(s1), string(s2))
+}
+
+// check verifies the B-tree invariants.
+func (t *Tree[K, V]) check(a *assert.Assertions) {
+	if t.root == nil {
+		a.Equal(0, t.size)
+		return
+	}
+	leafDepth := -1
+	var count int
+	var walk func(n *node[K, V], depth int, lo, hi *K)
+	walk = func(n *node[K, V], depth int, lo, hi *K) {
+		if n != t.root {
+			a.GreaterOrEqual(len(n.keys), t.degree-1)
+		}
+		a.LessOrEqual(len(n.keys), 2*t.degree-1)
+		a.Equal(len(n.keys), len(n.values))
+		count += len(n.keys)
+		for i, k := range n.keys {
+			if i > 0 {
+				a.Negative(t.compare(n.keys[i-1], k))
+			}
+			if lo != nil {
+				a.Positive(t.compare(k, *lo))
+			}
+			if hi != nil {
+				a.Negative(t.compare(k, *hi))
+			}
+		}
+		if n.leaf() {
+			if leafDepth < 0 {
+				leafDepth = depth
+			}
+			a.Equal(leafDepth, depth)
+			return
+		}
+		a.Equal(len(n.keys)+1, len(n.children))
+		for i, c := range n.children {
+			l, h := lo, hi
+			if i > 0 {
+				l = &n.keys[i-1]
+			}
+			if i < len(n.keys) {
+				h = &n.keys[i]
+			}
+			walk(c, depth+1, l, h)
+		}
+	}
+	walk(t.root, 1, nil, nil)
+	a.Equal(t.size, count)
+	a.Equal(leafDepth, t.Depth())
+}
+
+func TestPutGetDelete(t *testing.T) {
+	a := assert.New(t)
+
+	for _, degree := range []int{2, 3, 16} {
+		tr := New[compString, int](degree)
+		m := make(map[compString]int)
+		for i := 0; i < 10_000; i++ {
+			n := rand.Intn(1_000)
+			k := compString(fmt.Sprintf("k%d", n))
+			switch rand.Intn(3) {
+			case 0:
+				v, ok := tr.Delete(k)
+				mv, mok := m[k]
+				a.Equal(mok, ok)
+				a.Equal(mv, v)
+				delete(m, k)
+			case 1:
+				v, ok := tr.GetElsePut(k, func() int { return n })
+				mv, mok := m[k]
+				a.Equal(mok, ok)
+				if !mok {
+					m[k] = n
+					mv = n
+				}
+				a.Equal(mv, v)
+			default:
+				v, ok := tr.Put(k, i)
+				mv, mok := m[k]
+				a.Equal(mok, ok)
+				a.Equal(mv, v)
+				m[k] = i
+			}
+		}
+		tr.check(a)
+		a.Equal(len(m), tr.Size())
+		for k, v := range m {
+			tv, ok := tr.Get(k)
+			a.True(ok)
+			a.Equal(v, tv)
+		}
+
+		var keys []compString
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+		a.Equal(keys, tr.Keys())
+		a.Equal(keys[0], *tr.MinKey())
+		a.Equal(keys[len(keys)-1], *tr.MaxKey())
+
+		for _, k := range keys {
+			_, ok := tr.Delete(k)
+			a.True(ok)
+		}
+		tr.check(a)
+		a.Equal(0, tr.Size())
+		a.Equal(0, tr.Depth())
+		a.Nil(tr.MinKey())
+	}
+}
+
+func TestRange(t *testing.T) {
+	a := assert.New(t)
+
+	tr := NewOrdered[int, int](3)
+	for i := 0; i < 200; i += 2 {
+		tr.Put(i, i*10)
+	}
+	tr.check(a)
+
+	collect := func(lo, hi int, loInc, hiInc bool) []int {
+		var ks []int
+		tr.Range(lo, hi, loInc, hiInc, func(k, v int) bool {
+			a.Equal(k*10, v)
+			ks = append(ks, k)
+			return true
+		})
+		return ks
+	}
+	a.Equal([]int{10, 12, 14}, collect(10, 14, true, true))
+	a.Equal([]int{12}, collect(10, 14, false, false))
+	a.Equal([]int{12, 14}, collect(11, 15, true, true))
+	a.Equal([]int{0, 2}, collect(-5, 2, true, true))
+	a.Equal([]int{196, 198}, collect(195, 500, true, true))
+	a.Nil(collect(199, 500, true, true))
+	a.Len(collect(-1, 1000, true, true), 100)
+
+	var ks []int
+	a.False(tr.Range(0, 100, true, true, func(k, _ int) bool {
+		ks = append(ks, k)
+		return len(ks) < 3
+	}))
+	a.Equal([]int{0, 2, 4}, ks)
+	a.True(tr.Enumerate(func(int, int) bool { return true }))
+}
+
+func TestOrderedMap(t *testing.T) {
+	a := assert.New(t)
+
+	var m constraints.OrderedMap[int, int] = NewOrdered[int, int](2)
+	for i := 10; i > 0; i-- {
+		m.Put(i, i)
+	}
+	a.Equal(1, *m.MinKey())
+	a.Equal(10, m.Size())
+}
+
+func TestBadDegree(t *testing.T) {
+	a := assert.New(t)
+
+	a.Panics(func() { NewOrdered[int, int](1) })
+}
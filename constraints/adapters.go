@@ -0,0 +1,82 @@
+package constraints
+
+import "cmp"
+
+// Ordered wraps a value of a built-in ordered type to make it comparable.
+type Ordered[T cmp.Ordered] struct {
+	Value T
+}
+
+// Compare compares two wrapped values using the built-in ordering.
+func (o1 Ordered[T]) Compare(o2 Ordered[T]) int {
+	return cmp.Compare(o1.Value, o2.Value)
+}
+
+// Reverse wraps a comparable value to invert its order.
+type Reverse[T Comparable[T]] struct {
+	Value T
+}
+
+// Compare compares two wrapped values in the reverse order.
+func (r1 Reverse[T]) Compare(r2 Reverse[T]) int {
+	return r2.Value.Compare(r1.Value)
+}
+
+// Pair is a pair of comparable values ordered lexicographically.
+type Pair[A Comparable[A], B Comparable[B]] struct {
+	Fst A
+	Snd B
+}
+
+// Compare compares two pairs by their first components and then by their second components.
+func (p1 Pair[A, B]) Compare(p2 Pair[A, B]) int {
+	if c := p1.Fst.Compare(p2.Fst); c != 0 {
+		return c
+	}
+	return p1.Snd.Compare(p2.Snd)
+}
+
+// Triple is a triple of comparable values ordered lexicographically.
+type Triple[A Comparable[A], B Comparable[B], C Comparable[C]] struct {
+	Fst A
+	Snd B
+	Thd C
+}
+
+// Compare compares two triples component by component.
+func (t1 Triple[A, B, C]) Compare(t2 Triple[A, B, C]) int {
+	if c := t1.Fst.Compare(t2.Fst); c != 0 {
+		return c
+	}
+	if c := t1.Snd.Compare(t2.Snd); c != 0 {
+		return c
+	}
+	return t1.Thd.Compare(t2.Thd)
+}
+
+// Lex is a slice of comparable values ordered lexicographically.
+type Lex[T Comparable[T]] []T
+
+// Compare compares two slices element by element, a proper prefix being less than the longer slice.
+func (s1 Lex[T]) Compare(s2 Lex[T]) int {
+	for i, x := range s1 {
+		if i == len(s2) {
+			return 1
+		}
+		if c := x.Compare(s2[i]); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(s1), len(s2))
+}
+
+// Shortlex is a slice of comparable values ordered by length first and then lexicographically.
+type Shortlex[T Comparable[T]] []T
+
+// Compare compares two slices using the shortlex order.
+func (s1 Shortlex[T]) Compare(s2 Shortlex[T]) int {
+	if c := cmp.Compare(len(s1), len(s2)); c != 0 {
+		return c
+	}
+	return Lex[T](s1).Compare(Lex[T](s2))
+}
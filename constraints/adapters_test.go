@@ -0,0 +1,27 @@
+package constraints
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type ints = Ordered[int]
+
+func TestAdapters(t *testing.T) {
+	a := assert.New(t)
+
+	a.Negative(ints{1}.Compare(ints{2}))
+	a.Zero(Ordered[string]{"a"}.Compare(Ordered[string]{"a"}))
+	a.Positive(Reverse[ints]{ints{1}}.Compare(Reverse[ints]{ints{2}}))
+
+	a.Negative(Pair[ints, ints]{ints{1}, ints{9}}.Compare(Pair[ints, ints]{ints{2}, ints{0}}))
+	a.Positive(Pair[ints, ints]{ints{1}, ints{9}}.Compare(Pair[ints, ints]{ints{1}, ints{0}}))
+	a.Zero(Triple[ints, ints, ints]{ints{1}, ints{2}, ints{3}}.Compare(Triple[ints, ints, ints]{ints{1}, ints{2}, ints{3}}))
+
+	a.Negative(Lex[ints]{{1}, {2}}.Compare(Lex[ints]{{1}, {2}, {0}}))
+	a.Positive(Lex[ints]{{2}}.Compare(Lex[ints]{{1}, {2}}))
+	a.Zero(Lex[ints]{}.Compare(nil))
+	a.Negative(Shortlex[ints]{{2}}.Compare(Shortlex[ints]{{1}, {2}}))
+	a.Positive(Shortlex[ints]{{1}, {3}}.Compare(Shortlex[ints]{{1}, {2}}))
+}
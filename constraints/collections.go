@@ -0,0 +1,22 @@
+package constraints
+
+// OrderedMap is a map whose keys are enumerated in ascending order.
+type OrderedMap[K any, V any] interface {
+	Put(key K, value V) (oldValue V, updated bool)
+	Get(key K) (retValue V, found bool)
+	GetElsePut(key K, fnValue func() V) (retValue V, updated bool)
+	Keys() []K
+	Enumerate(f func(K, V) bool) bool
+	MinKey() *K
+	Size() int
+}
+
+// OrderedSet is a set whose elements are enumerated in ascending order.
+type OrderedSet[K any] interface {
+	Insert(key K) bool
+	Contains(key K) bool
+	Values() []K
+	Enumerate(f func(K) bool) bool
+	MinKey() *K
+	Size() int
+}
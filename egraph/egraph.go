@@ -2,8 +2,11 @@ package egraph
 
 import (
 	"fmt"
+	"iter"
 	"strings"
 
+	"github.com/fealsamh/datastructures/constraints"
+	"github.com/fealsamh/datastructures/hashmap"
 	"github.com/fealsamh/datastructures/logic"
 	"github.com/fealsamh/datastructures/redblack"
 	"github.com/fealsamh/datastructures/sahuaro"
@@ -38,6 +41,8 @@ func (n1 *eNode) Compare(n2 *eNode) int {
 type eClass struct {
 	eNodes      *redblack.Set[*eNode]
 	parentNodes *redblack.Set[*eNode]
+	// the IDs mapped to the e-class
+	ids []eClassID
 }
 
 // Graph is an e-graph.
@@ -45,15 +50,48 @@ type Graph struct {
 	maxID     int
 	eClassIds *unionfind.Structure[eClassID]
 	hashcons  *redblack.Tree[*eNode, *sahuaro.Tree[eClassID]]
-	eClasses  *redblack.Tree[eClassID, *eClass]
+	eClasses  constraints.OrderedMap[eClassID, *eClass]
+	onChange  func(id int)
+}
+
+// Option configures an e-graph.
+type Option func(*Graph)
+
+// WithHashMaps makes an e-graph index its e-classes by ID in hash maps instead of red-black trees.
+func WithHashMaps() Option {
+	return func(g *Graph) {
+		g.eClassIds = unionfind.New(unionfind.WithMap(hashmap.New[eClassID, *sahuaro.Tree[eClassID]]()))
+		g.eClasses = hashmap.New[eClassID, *eClass]()
+	}
 }
 
 // New creates a new e-graph.
-func New() *Graph {
-	return &Graph{
-		eClassIds: unionfind.New[eClassID](),
-		hashcons:  redblack.NewTree[*eNode, *sahuaro.Tree[eClassID]](),
-		eClasses:  redblack.NewTree[eClassID, *eClass](),
+func New(opts ...Option) *Graph {
+	g := &Graph{
+		hashcons: redblack.NewTree[*eNode, *sahuaro.Tree[eClassID]](),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if g.eClassIds == nil {
+		g.eClassIds = unionfind.New[eClassID]()
+	}
+	if g.eClasses == nil {
+		g.eClasses = redblack.NewTree[eClassID, *eClass]()
+	}
+	return g
+}
+
+// OnEClassMapChange registers a callback invoked with the ID of every e-class
+// whose entry in the e-class map is added or replaced.
+func (g *Graph) OnEClassMapChange(f func(id int)) {
+	g.onChange = f
+}
+
+func (g *Graph) putEClass(id eClassID, cls *eClass) {
+	g.eClasses.Put(id, cls)
+	if g.onChange != nil {
+		g.onChange(int(id))
 	}
 }
 
@@ -66,23 +104,31 @@ func (g *Graph) Dump() {
 
 // Classes returns all the e-classes of the e-graph.
 func (g *Graph) Classes() [][]*logic.Term {
-	processed := make(map[*eClass]struct{})
 	var r [][]*logic.Term
-	for _, id := range g.eClasses.Keys() {
-		cls, _ := g.eClasses.Get(id)
-		if _, ok := processed[cls]; ok {
-			continue
-		}
-		processed[cls] = struct{}{}
-		terms := redblack.NewSet[*logic.Term]()
-		for _, n := range cls.eNodes.Values() {
-			terms.Insert(g.getTerm(n))
-		}
-		r = append(r, terms.Values())
+	for terms := range g.ClassesSeq() {
+		r = append(r, terms)
 	}
 	return r
 }
 
+// ClassesSeq returns an iterator over the e-classes of the e-graph.
+func (g *Graph) ClassesSeq() iter.Seq[[]*logic.Term] {
+	return func(yield func([]*logic.Term) bool) {
+		processed := make(map[*eClass]struct{})
+		g.eClasses.Enumerate(func(_ eClassID, cls *eClass) bool {
+			if _, ok := processed[cls]; ok {
+				return true
+			}
+			processed[cls] = struct{}{}
+			terms := redblack.NewSet[*logic.Term]()
+			for _, n := range cls.eNodes.Values() {
+				terms.Insert(g.getTerm(n))
+			}
+			return yield(terms.Values())
+		})
+	}
+}
+
 // Merge merges two n-ary terms.
 func (g *Graph) Merge(t1, t2 *logic.Term) {
 	_, clsID1, ok := g.getENode(t1, false)
@@ -102,18 +148,12 @@ func (g *Graph) merge(clsID1, clsID2 *sahuaro.Tree[eClassID]) {
 	}
 	cls1, _ := g.eClasses.Get(clsID1.Value)
 	cls2, _ := g.eClasses.Get(clsID2.Value)
-	for _, cls := range cls2.eNodes.Values() {
-		cls1.eNodes.Insert(cls)
-	}
-	for _, cls := range cls2.parentNodes.Values() {
-		cls1.parentNodes.Insert(cls)
+	cls1.eNodes.UnionWith(cls2.eNodes)
+	cls1.parentNodes.UnionWith(cls2.parentNodes)
+	for _, id := range cls2.ids {
+		g.putEClass(id, cls1)
 	}
-	g.eClasses.Enumerate(func(id eClassID, cls *eClass) bool {
-		if cls == cls2 {
-			g.eClasses.Put(id, cls1)
-		}
-		return true
-	})
+	cls1.ids = append(cls1.ids, cls2.ids...)
 	parentNodes := cls1.parentNodes.Values()
 	// preserving the congruence invariant
 	for i, n1 := range parentNodes {
@@ -175,9 +215,10 @@ func (g *Graph) getEClassID(n *eNode, create bool) (*sahuaro.Tree[eClassID], boo
 			cls := &eClass{
 				eNodes:      redblack.NewSet[*eNode](),
 				parentNodes: redblack.NewSet[*eNode](),
+				ids:         []eClassID{clsID},
 			}
 			cls.eNodes.Insert(n)
-			g.eClasses.Put(clsID, cls)
+			g.putEClass(clsID, cls)
 			return t, false
 		}
 		return nil, false
@@ -0,0 +1,40 @@
+package egraph
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fealsamh/datastructures/logic"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackends(t *testing.T) {
+	a := assert.New(t)
+
+	for _, g := range []*Graph{New(), New(WithHashMaps())} {
+		changed := make(map[int]int)
+		g.OnEClassMapChange(func(id int) { changed[id]++ })
+
+		fa, fb := logic.NewTerm("f", "a"), logic.NewTerm("f", "b")
+		a.False(g.Add(fa))
+		a.False(g.Add(fb))
+		a.True(g.Add(fa))
+		a.Len(g.Classes(), 4)
+		// a, f(a), b and f(b) got their e-classes
+		a.Equal(map[int]int{1: 1, 2: 1, 3: 1, 4: 1}, changed)
+
+		g.Merge(fa.Args[0], fb.Args[0])
+		a.True(g.CheckEClassMap())
+		// f(a) and f(b) are merged by congruence and printed through the representative of their argument
+		a.Equal("[[a b] [f(a)]]", fmt.Sprint(g.Classes()))
+		r, ok := g.Get(fb)
+		a.True(ok)
+		a.Equal("f(a)", r.String())
+		// the IDs of the absorbed e-classes of b and f(b) got remapped
+		a.Equal(3, changed[1]+changed[3])
+		a.Equal(3, changed[2]+changed[4])
+
+		_, ok = g.Get(logic.NewTerm("g", "a"))
+		a.False(ok)
+	}
+}
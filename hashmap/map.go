@@ -0,0 +1,108 @@
+package hashmap
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/fealsamh/datastructures/constraints"
+)
+
+// Map is a hash map that orders its keys on demand.
+// Lookups and insertions take constant time but Keys and Enumerate sort all the keys on every call
+// in O(n log n) time and MinKey scans them in O(n) time.
+type Map[K comparable, V any] struct {
+	items   map[K]V
+	compare func(K, K) int
+}
+
+// New creates a new hash map.
+func New[K interface {
+	comparable
+	constraints.Comparable[K]
+}, V any]() *Map[K, V] {
+	return NewFunc[K, V](func(k1, k2 K) int { return k1.Compare(k2) })
+}
+
+// NewOrdered creates a new hash map whose keys are ordered by the built-in ordering.
+func NewOrdered[K cmp.Ordered, V any]() *Map[K, V] {
+	return NewFunc[K, V](cmp.Compare[K])
+}
+
+// NewFunc creates a new hash map whose keys are ordered by a comparison function.
+func NewFunc[K comparable, V any](compare func(K, K) int) *Map[K, V] {
+	return &Map[K, V]{items: make(map[K]V), compare: compare}
+}
+
+// Size returns the size of the map.
+func (m *Map[K, V]) Size() int {
+	return len(m.items)
+}
+
+// Keys returns the keys of the items in the map in ascending order.
+func (m *Map[K, V]) Keys() []K {
+	if len(m.items) == 0 {
+		return nil
+	}
+	ks := make([]K, 0, len(m.items))
+	for k := range m.items {
+		ks = append(ks, k)
+	}
+	slices.SortFunc(ks, m.compare)
+	return ks
+}
+
+// Enumerate enumerates all the items in the map in ascending order.
+// Items inserted during the enumeration aren't visited and deleted ones are skipped.
+func (m *Map[K, V]) Enumerate(f func(K, V) bool) bool {
+	for _, k := range m.Keys() {
+		v, ok := m.items[k]
+		if !ok {
+			continue
+		}
+		if !f(k, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// MinKey returns the minimum key in the map or nil if the map is empty.
+func (m *Map[K, V]) MinKey() *K {
+	var min *K
+	for k := range m.items {
+		if min == nil || m.compare(k, *min) < 0 {
+			min = &k
+		}
+	}
+	return min
+}
+
+// Put inserts a new key-value pair into the map or replaces the value for an existing key.
+func (m *Map[K, V]) Put(key K, value V) (oldValue V, updated bool) {
+	oldValue, updated = m.items[key]
+	m.items[key] = value
+	return
+}
+
+// GetElsePut returns the value for the given key or inserts a new key-value pair into the map.
+func (m *Map[K, V]) GetElsePut(key K, fnValue func() V) (retValue V, updated bool) {
+	if v, ok := m.items[key]; ok {
+		return v, true
+	}
+	retValue = fnValue()
+	m.items[key] = retValue
+	return
+}
+
+// Get returns the value for the given key.
+func (m *Map[K, V]) Get(key K) (retValue V, found bool) {
+	retValue, found = m.items[key]
+	return
+}
+
+// Delete removes the key from the map and returns its former value.
+func (m *Map[K, V]) Delete(key K) (oldValue V, deleted bool) {
+	oldValue, deleted = m.items[key]
+	delete(m.items, key)
+	return
+}
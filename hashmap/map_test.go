@@ -0,0 +1,46 @@
+package hashmap
+
+import (
+	"testing"
+
+	"github.com/fealsamh/datastructures/constraints"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMap(t *testing.T) {
+	a := assert.New(t)
+
+	var m constraints.OrderedMap[int, string] = NewOrdered[int, string]()
+	a.Nil(m.MinKey())
+	a.Nil(m.Keys())
+	for _, k := range []int{5, 1, 4, 2, 3} {
+		_, ok := m.Put(k, "v")
+		a.False(ok)
+	}
+	old, ok := m.Put(4, "w")
+	a.True(ok)
+	a.Equal("v", old)
+	v, ok := m.GetElsePut(6, func() string { return "x" })
+	a.False(ok)
+	a.Equal("x", v)
+	v, ok = m.GetElsePut(6, func() string { return "y" })
+	a.True(ok)
+	a.Equal("x", v)
+
+	a.Equal(6, m.Size())
+	a.Equal(1, *m.MinKey())
+	a.Equal([]int{1, 2, 3, 4, 5, 6}, m.Keys())
+	v, ok = m.Get(4)
+	a.True(ok)
+	a.Equal("w", v)
+
+	hm := m.(*Map[int, string])
+	var ks []int
+	a.False(m.Enumerate(func(k int, _ string) bool {
+		ks = append(ks, k)
+		hm.Delete(k + 1)
+		return k < 5
+	}))
+	a.Equal([]int{1, 3, 5}, ks)
+	a.Equal(3, m.Size())
+}
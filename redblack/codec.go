@@ -0,0 +1,320 @@
+package redblack
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Codec converts values of a type to bytes and back.
+type Codec[T any] interface {
+	Marshal(T) ([]byte, error)
+	Unmarshal([]byte) (T, error)
+}
+
+// GobCodec is a codec based on encoding/gob.
+type GobCodec[T any] struct{}
+
+// Marshal encodes a value using encoding/gob.
+func (GobCodec[T]) Marshal(v T) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes a value using encoding/gob.
+func (GobCodec[T]) Unmarshal(b []byte) (T, error) {
+	var v T
+	err := gob.NewDecoder(bytes.NewReader(b)).Decode(&v)
+	return v, err
+}
+
+// JSONCodec is a codec based on encoding/json.
+type JSONCodec[T any] struct{}
+
+// Marshal encodes a value using encoding/json.
+func (JSONCodec[T]) Marshal(v T) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal decodes a value using encoding/json.
+func (JSONCodec[T]) Unmarshal(b []byte) (T, error) {
+	var v T
+	err := json.Unmarshal(b, &v)
+	return v, err
+}
+
+// formatVersion is the version of the serialization formats written by this package.
+const formatVersion = 1
+
+var (
+	treeMagic = []byte("RBT")
+	setMagic  = []byte("RBS")
+)
+
+// MarshalBinary encodes the tree using GobCodec for both keys and values.
+func (t *Tree[K, V]) MarshalBinary() ([]byte, error) {
+	return t.MarshalBinaryWith(GobCodec[K]{}, GobCodec[V]{})
+}
+
+// MarshalBinaryWith encodes the tree using the given codecs for keys and values.
+func (t *Tree[K, V]) MarshalBinaryWith(keyCodec Codec[K], valueCodec Codec[V]) ([]byte, error) {
+	b := appendHeader(nil, treeMagic, t.Size())
+	var err error
+	t.Enumerate(func(k K, v V) bool {
+		if b, err = appendEncoded(b, keyCodec, k); err != nil {
+			return false
+		}
+		b, err = appendEncoded(b, valueCodec, v)
+		return err == nil
+	})
+	return b, err
+}
+
+// UnmarshalBinary decodes the tree using GobCodec for both keys and values, replacing its contents.
+// A zero-value tree can only be decoded if its keys implement constraints.Comparable.
+func (t *Tree[K, V]) UnmarshalBinary(data []byte) error {
+	return t.UnmarshalBinaryWith(data, GobCodec[K]{}, GobCodec[V]{})
+}
+
+// UnmarshalBinaryWith decodes the tree using the given codecs for keys and values, replacing its contents.
+// A zero-value tree can only be decoded if its keys implement constraints.Comparable.
+func (t *Tree[K, V]) UnmarshalBinaryWith(data []byte, keyCodec Codec[K], valueCodec Codec[V]) error {
+	if err := t.checkComparator("tree"); err != nil {
+		return err
+	}
+	n, data, err := readHeader(data, treeMagic)
+	if err != nil {
+		return err
+	}
+	keys, values := make([]K, 0, n), make([]V, 0, n)
+	for i := 0; i < n; i++ {
+		var (
+			k K
+			v V
+		)
+		if k, data, err = readEncoded(data, keyCodec); err != nil {
+			return err
+		}
+		if v, data, err = readEncoded(data, valueCodec); err != nil {
+			return err
+		}
+		keys, values = append(keys, k), append(values, v)
+	}
+	if len(data) > 0 {
+		return errors.New("trailing data after red-black tree")
+	}
+	return t.replaceSorted(keys, values)
+}
+
+type jsonItem[K any, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+type jsonTree[T any] struct {
+	Version int `json:"version"`
+	Items   []T `json:"items"`
+}
+
+// MarshalJSON encodes the tree as a versioned array of key-value pairs in ascending key order.
+func (t *Tree[K, V]) MarshalJSON() ([]byte, error) {
+	items := make([]jsonItem[K, V], 0, t.Size())
+	t.Enumerate(func(k K, v V) bool {
+		items = append(items, jsonItem[K, V]{Key: k, Value: v})
+		return true
+	})
+	return json.Marshal(jsonTree[jsonItem[K, V]]{Version: formatVersion, Items: items})
+}
+
+// UnmarshalJSON decodes the tree from a versioned array of key-value pairs in ascending key order, replacing its contents.
+// A zero-value tree can only be decoded if its keys implement constraints.Comparable.
+// As usual, `null` leaves the tree unchanged.
+func (t *Tree[K, V]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	if err := t.checkComparator("tree"); err != nil {
+		return err
+	}
+	var jt jsonTree[jsonItem[K, V]]
+	if err := json.Unmarshal(data, &jt); err != nil {
+		return err
+	}
+	if err := checkVersion(jt.Version); err != nil {
+		return err
+	}
+	keys, values := make([]K, len(jt.Items)), make([]V, len(jt.Items))
+	for i, it := range jt.Items {
+		keys[i], values[i] = it.Key, it.Value
+	}
+	return t.replaceSorted(keys, values)
+}
+
+// MarshalBinary encodes the set using GobCodec for the elements.
+func (s *Set[K]) MarshalBinary() ([]byte, error) {
+	return s.MarshalBinaryWith(GobCodec[K]{})
+}
+
+// MarshalBinaryWith encodes the set using the given codec for the elements.
+func (s *Set[K]) MarshalBinaryWith(codec Codec[K]) ([]byte, error) {
+	b := appendHeader(nil, setMagic, s.Size())
+	var err error
+	(*Tree[K, struct{}])(s).Enumerate(func(k K, _ struct{}) bool {
+		b, err = appendEncoded(b, codec, k)
+		return err == nil
+	})
+	return b, err
+}
+
+// UnmarshalBinary decodes the set using GobCodec for the elements, replacing its contents.
+// A zero-value set can only be decoded if its elements implement constraints.Comparable.
+func (s *Set[K]) UnmarshalBinary(data []byte) error {
+	return s.UnmarshalBinaryWith(data, GobCodec[K]{})
+}
+
+// UnmarshalBinaryWith decodes the set using the given codec for the elements, replacing its contents.
+// A zero-value set can only be decoded if its elements implement constraints.Comparable.
+func (s *Set[K]) UnmarshalBinaryWith(data []byte, codec Codec[K]) error {
+	if err := (*Tree[K, struct{}])(s).checkComparator("set"); err != nil {
+		return err
+	}
+	n, data, err := readHeader(data, setMagic)
+	if err != nil {
+		return err
+	}
+	keys := make([]K, 0, n)
+	for i := 0; i < n; i++ {
+		var k K
+		if k, data, err = readEncoded(data, codec); err != nil {
+			return err
+		}
+		keys = append(keys, k)
+	}
+	if len(data) > 0 {
+		return errors.New("trailing data after red-black set")
+	}
+	return (*Tree[K, struct{}])(s).replaceSorted(keys, nil)
+}
+
+// MarshalJSON encodes the set as a versioned array of elements in ascending order.
+func (s *Set[K]) MarshalJSON() ([]byte, error) {
+	items := s.Values()
+	if items == nil {
+		items = []K{}
+	}
+	return json.Marshal(jsonTree[K]{Version: formatVersion, Items: items})
+}
+
+// UnmarshalJSON decodes the set from a versioned array of elements in ascending order, replacing its contents.
+// A zero-value set can only be decoded if its elements implement constraints.Comparable.
+// As usual, `null` leaves the set unchanged.
+func (s *Set[K]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	if err := (*Tree[K, struct{}])(s).checkComparator("set"); err != nil {
+		return err
+	}
+	var jt jsonTree[K]
+	if err := json.Unmarshal(data, &jt); err != nil {
+		return err
+	}
+	if err := checkVersion(jt.Version); err != nil {
+		return err
+	}
+	return (*Tree[K, struct{}])(s).replaceSorted(jt.Items, nil)
+}
+
+// checkComparator returns an error if the keys of a zero-value tree can't be ordered.
+// Struct fields are allocated without a comparison function by the decoders.
+func (t *Tree[K, V]) checkComparator(what string) error {
+	if t.compare != nil {
+		return nil
+	}
+	if !keysComparable[K]() {
+		var k K
+		return fmt.Errorf("red-black %s of %T keys without comparison function", what, k)
+	}
+	return nil
+}
+
+func (t *Tree[K, V]) replaceSorted(keys []K, values []V) error {
+	if err := checkSorted(t.resolveComparator(), keys); err != nil {
+		return err
+	}
+	t.freeSubtree(t.root)
+	t.build(keys, values)
+	return nil
+}
+
+func appendHeader(b, magic []byte, n int) []byte {
+	b = append(b, magic...)
+	b = binary.AppendUvarint(b, formatVersion)
+	return binary.AppendUvarint(b, uint64(n))
+}
+
+func readHeader(data, magic []byte) (int, []byte, error) {
+	if !bytes.HasPrefix(data, magic) {
+		return 0, nil, fmt.Errorf("missing '%s' header", magic)
+	}
+	data = data[len(magic):]
+	version, data, err := readUvarint(data)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := checkVersion(int(version)); err != nil {
+		return 0, nil, err
+	}
+	n, data, err := readUvarint(data)
+	if err != nil {
+		return 0, nil, err
+	}
+	// every item takes at least one byte
+	if n > uint64(len(data)) {
+		return 0, nil, fmt.Errorf("item count %d exceeds data size", n)
+	}
+	return int(n), data, nil
+}
+
+func checkVersion(version int) error {
+	if version < 1 || version > formatVersion {
+		return fmt.Errorf("unsupported format version %d", version)
+	}
+	return nil
+}
+
+func readUvarint(data []byte) (uint64, []byte, error) {
+	x, n := binary.Uvarint(data)
+	if n <= 0 {
+		return 0, nil, errors.New("malformed varint")
+	}
+	return x, data[n:], nil
+}
+
+func appendEncoded[T any](b []byte, codec Codec[T], v T) ([]byte, error) {
+	enc, err := codec.Marshal(v)
+	if err != nil {
+		return b, err
+	}
+	b = binary.AppendUvarint(b, uint64(len(enc)))
+	return append(b, enc...), nil
+}
+
+func readEncoded[T any](data []byte, codec Codec[T]) (T, []byte, error) {
+	var v T
+	n, data, err := readUvarint(data)
+	if err != nil {
+		return v, nil, err
+	}
+	if n > uint64(len(data)) {
+		return v, nil, errors.New("truncated data")
+	}
+	v, err = codec.Unmarshal(data[:n])
+	return v, data[n:], err
+}
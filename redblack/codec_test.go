@@ -0,0 +1,132 @@
+package redblack
+
+import (
+	"cmp"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type intCodec struct{}
+
+func (intCodec) Marshal(i int) ([]byte, error) { return []byte(strconv.Itoa(i)), nil }
+
+func (intCodec) Unmarshal(b []byte) (int, error) { return strconv.Atoi(string(b)) }
+
+func TestBinarySerialization(t *testing.T) {
+	a := assert.New(t)
+
+	tr := NewOrderedTree[int, string]()
+	for i := 0; i < 100; i++ {
+		tr.Put(i*7%101, strconv.Itoa(i))
+	}
+
+	b, err := tr.MarshalBinary()
+	a.NoError(err)
+	tr2 := NewOrderedTree[int, string]()
+	a.NoError(tr2.UnmarshalBinary(b))
+	a.NoError(tr2.CheckInvariants())
+	a.Equal(tr.Keys(), tr2.Keys())
+	v, _ := tr2.Get(7)
+	a.Equal("1", v)
+
+	b, err = tr.MarshalBinaryWith(intCodec{}, JSONCodec[string]{})
+	a.NoError(err)
+	tr3 := NewOrderedTree[int, string]()
+	a.NoError(tr3.UnmarshalBinaryWith(b, intCodec{}, JSONCodec[string]{}))
+	a.Equal(tr.Keys(), tr3.Keys())
+
+	a.Error(tr3.UnmarshalBinaryWith(b[:len(b)-1], intCodec{}, JSONCodec[string]{}))
+	a.Error(tr3.UnmarshalBinary([]byte("RBT\x02\x00")))
+	a.Error(tr3.UnmarshalBinary([]byte("XYZ")))
+	a.Error(new(Tree[int, string]).UnmarshalBinary(b))
+
+	s := NewOrderedSet[string]()
+	s.Insert("b")
+	s.Insert("a")
+	b, err = s.MarshalBinary()
+	a.NoError(err)
+	s2 := NewOrderedSet[string]()
+	a.NoError(s2.UnmarshalBinary(b))
+	a.Equal([]string{"a", "b"}, s2.Values())
+	a.Error(tr2.UnmarshalBinary(b))
+}
+
+func TestJSONSerialization(t *testing.T) {
+	a := assert.New(t)
+
+	tr := NewOrderedTree[string, int]()
+	tr.Put("b", 2)
+	tr.Put("a", 1)
+	b, err := json.Marshal(tr)
+	a.NoError(err)
+	a.JSONEq(`{"version":1,"items":[{"key":"a","value":1},{"key":"b","value":2}]}`, string(b))
+
+	tr2 := NewOrderedTree[string, int]()
+	a.NoError(json.Unmarshal(b, tr2))
+	a.Equal(tr.Keys(), tr2.Keys())
+	a.Error(json.Unmarshal([]byte(`{"version":1,"items":[{"key":"b"},{"key":"a"}]}`), tr2))
+	a.Error(json.Unmarshal([]byte(`{"version":9,"items":[]}`), tr2))
+
+	s := NewOrderedSet[int]()
+	b, err = json.Marshal(s)
+	a.NoError(err)
+	a.JSONEq(`{"version":1,"items":[]}`, string(b))
+	a.NoError(json.Unmarshal([]byte(`{"version":1,"items":[1,2,3]}`), s))
+	a.Equal([]int{1, 2, 3}, s.Values())
+}
+
+func TestJSONStructFields(t *testing.T) {
+	a := assert.New(t)
+
+	type index struct {
+		Tree *Tree[compString, int] `json:"tree"`
+		Set  Set[compString]        `json:"set"`
+	}
+	in := index{Tree: NewTree[compString, int]()}
+	in.Tree.Put("b", 2)
+	in.Tree.Put("a", 1)
+	in.Set.Insert("x")
+	b, err := json.Marshal(&in)
+	a.NoError(err)
+
+	var out index
+	a.NoError(json.Unmarshal(b, &out))
+	a.Equal([]compString{"a", "b"}, out.Tree.Keys())
+	a.Equal([]compString{"x"}, out.Set.Values())
+
+	a.NoError(json.Unmarshal([]byte(`{"tree":null,"set":null}`), &out))
+	a.Nil(out.Tree)
+	a.Equal([]compString{"x"}, out.Set.Values())
+	a.NoError(out.Set.UnmarshalJSON([]byte("null")))
+	a.Equal(1, out.Set.Size())
+
+	var ints struct{ Tree *Tree[int, int] }
+	a.EqualError(json.Unmarshal([]byte(`{"Tree":{"version":1,"items":[]}}`), &ints),
+		"red-black tree of int keys without comparison function")
+}
+
+func TestUnmarshalIntoPooledTree(t *testing.T) {
+	a := assert.New(t)
+
+	pool := NewNodePool[int, string](16)
+	tr := NewTreeFuncWithPool(cmp.Compare[int], pool)
+	for i := 0; i < 100; i++ {
+		tr.Put(i, strconv.Itoa(i))
+	}
+	b, err := tr.MarshalBinary()
+	a.NoError(err)
+	slab, pos := pool.slab, pool.pos
+	for i := 0; i < 10; i++ {
+		// the old nodes are recycled rather than leaked
+		a.NoError(tr.UnmarshalBinary(b))
+		a.Equal(slab, pool.slab)
+		a.Equal(pos, pool.pos)
+	}
+	a.NoError(tr.CheckInvariants())
+	a.Equal(100, tr.Size())
+	v, _ := tr.Get(42)
+	a.Equal("42", v)
+}
@@ -0,0 +1,106 @@
+package redblack
+
+// Cursor is a bidirectional cursor over a red-black tree.
+type Cursor[K any, V any] struct {
+	tree *Tree[K, V]
+	node *node[K, V]
+}
+
+// Cursor creates a new cursor over the tree. The cursor isn't positioned initially.
+func (t *Tree[K, V]) Cursor() *Cursor[K, V] {
+	return &Cursor[K, V]{tree: t}
+}
+
+// Valid returns true if the cursor is positioned at an item.
+func (c *Cursor[K, V]) Valid() bool {
+	return c.node != nil
+}
+
+// Seek positions the cursor at the item with the least key greater than or equal to the given key.
+func (c *Cursor[K, V]) Seek(key K) bool {
+	c.node = c.tree.root.ceiling(c.tree.comparator(), key, true)
+	return c.node != nil
+}
+
+// SeekLE positions the cursor at the item with the greatest key less than or equal to the given key.
+func (c *Cursor[K, V]) SeekLE(key K) bool {
+	c.node = c.tree.root.floor(c.tree.comparator(), key, true)
+	return c.node != nil
+}
+
+// First positions the cursor at the item with the minimum key.
+func (c *Cursor[K, V]) First() bool {
+	c.node = nil
+	if c.tree.root != nil {
+		c.node = c.tree.root.min()
+	}
+	return c.node != nil
+}
+
+// Last positions the cursor at the item with the maximum key.
+func (c *Cursor[K, V]) Last() bool {
+	c.node = nil
+	if c.tree.root != nil {
+		c.node = c.tree.root.max()
+	}
+	return c.node != nil
+}
+
+// Next moves the cursor to the next item.
+func (c *Cursor[K, V]) Next() bool {
+	if c.node != nil {
+		c.node = c.node.next()
+	}
+	return c.node != nil
+}
+
+// Prev moves the cursor to the previous item.
+func (c *Cursor[K, V]) Prev() bool {
+	if c.node != nil {
+		c.node = c.node.prev()
+	}
+	return c.node != nil
+}
+
+// Key returns the key of the current item.
+// It panics if the cursor isn't positioned.
+func (c *Cursor[K, V]) Key() K {
+	return c.mustNode().key
+}
+
+// Value returns the value of the current item.
+// It panics if the cursor isn't positioned.
+func (c *Cursor[K, V]) Value() V {
+	return c.mustNode().value
+}
+
+// SetValue replaces the value of the current item in place.
+// It panics if the cursor isn't positioned.
+func (c *Cursor[K, V]) SetValue(value V) {
+	n := c.mustNode()
+	oldValue := n.value
+	n.value = value
+	if c.tree.augment != nil {
+		n.updatePath(c.tree)
+	}
+	c.tree.updated(n.key, oldValue, value)
+}
+
+// Delete removes the current item from the tree and moves the cursor to the next item.
+// It panics if the cursor isn't positioned.
+func (c *Cursor[K, V]) Delete() bool {
+	n := c.mustNode()
+	c.node = n.next()
+	n.remove(c.tree)
+	key, value := n.key, n.value
+	c.tree.freeNode(n)
+	c.tree.deleted(key, value)
+	return c.node != nil
+}
+
+func (c *Cursor[K, V]) mustNode() *node[K, V] {
+	if c.node == nil {
+		panic("red-black cursor not positioned")
+	}
+	return c.node
+}
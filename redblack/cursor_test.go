@@ -0,0 +1,54 @@
+package redblack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor(t *testing.T) {
+	a := assert.New(t)
+
+	tr := NewTree[compString, int]()
+	for i := 0; i < 100; i++ {
+		tr.Put(compString(fmt.Sprintf("k%02d", i)), i)
+	}
+
+	c := tr.Cursor()
+	a.False(c.Valid())
+	var ks []compString
+	for ok := c.First(); ok; ok = c.Next() {
+		ks = append(ks, c.Key())
+	}
+	a.Equal(tr.Keys(), ks)
+
+	a.True(c.Last())
+	a.Equal(compString("k99"), c.Key())
+	a.True(c.Prev())
+	a.Equal(98, c.Value())
+
+	a.True(c.Seek("k10a"))
+	a.Equal(compString("k11"), c.Key())
+	c.SetValue(-11)
+	v, _ := tr.Get("k11")
+	a.Equal(-11, v)
+	a.True(c.SeekLE("k10a"))
+	a.Equal(compString("k10"), c.Key())
+	a.False(c.Seek("z"))
+
+	// deleting every other item while walking
+	for ok := c.First(); ok; ok = c.Next() {
+		if !c.Delete() {
+			break
+		}
+	}
+	a.Equal(50, tr.Size())
+	a.True(tr.Check())
+	a.True(c.First())
+	a.Equal(compString("k01"), c.Key())
+	a.Panics(func() {
+		c.node = nil
+		c.Key()
+	})
+}
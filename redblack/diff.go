@@ -0,0 +1,111 @@
+package redblack
+
+// ChangeKind is the kind of a difference between two trees.
+type ChangeKind byte
+
+const (
+	// Added means the key is only in the second tree.
+	Added ChangeKind = iota
+	// Removed means the key is only in the first tree.
+	Removed
+	// Changed means the key is in both trees with different values.
+	Changed
+)
+
+// String returns the name of the change kind.
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Changed:
+		return "changed"
+	}
+	return "unknown"
+}
+
+// Change is a difference between two trees.
+// OldValue is set for removed and changed keys, NewValue for added and changed keys.
+type Change[K any, V any] struct {
+	Kind     ChangeKind
+	Key      K
+	OldValue V
+	NewValue V
+}
+
+// Diff compares two trees ordered the same way and returns the changes turning `a` into `b` in ascending key order.
+// The values for a key found in both trees are compared using `eq`. It runs in linear time.
+func Diff[K any, V any](a, b *Tree[K, V], eq func(V, V) bool) []Change[K, V] {
+	var changes []Change[K, V]
+	walk(a, b, func(na, nb *node[K, V]) {
+		switch {
+		case nb == nil:
+			changes = append(changes, Change[K, V]{Kind: Removed, Key: na.key, OldValue: na.value})
+		case na == nil:
+			changes = append(changes, Change[K, V]{Kind: Added, Key: nb.key, NewValue: nb.value})
+		case !eq(na.value, nb.value):
+			changes = append(changes, Change[K, V]{Kind: Changed, Key: na.key, OldValue: na.value, NewValue: nb.value})
+		}
+	})
+	return changes
+}
+
+// Merge creates a new tree ordered, augmented and allocated like `a` with the items of two trees ordered the same way.
+// The values for a key found in both trees are combined using `resolve`. It runs in linear time.
+func Merge[K any, V any](a, b *Tree[K, V], resolve func(key K, va, vb V) V) *Tree[K, V] {
+	var (
+		keys   []K
+		values []V
+	)
+	walk(a, b, func(na, nb *node[K, V]) {
+		switch {
+		case nb == nil:
+			keys, values = append(keys, na.key), append(values, na.value)
+		case na == nil:
+			keys, values = append(keys, nb.key), append(values, nb.value)
+		default:
+			keys, values = append(keys, na.key), append(values, resolve(na.key, na.value, nb.value))
+		}
+	})
+	m := a.newEmpty()
+	m.build(keys, values)
+	return m
+}
+
+// walk walks two trees in ascending key order calling `f` with the nodes for each key, either of which may be nil.
+func walk[K any, V any](a, b *Tree[K, V], f func(na, nb *node[K, V])) {
+	var na, nb *node[K, V]
+	if a.root != nil {
+		na = a.root.min()
+	}
+	if b.root != nil {
+		nb = b.root.min()
+	}
+	var compare func(K, K) int
+	if na != nil && nb != nil {
+		compare = a.comparator()
+	}
+	for na != nil || nb != nil {
+		var c int
+		switch {
+		case na == nil:
+			c = 1
+		case nb == nil:
+			c = -1
+		default:
+			c = compare(na.key, nb.key)
+		}
+		switch {
+		case c < 0:
+			f(na, nil)
+			na = na.next()
+		case c > 0:
+			f(nil, nb)
+			nb = nb.next()
+		default:
+			f(na, nb)
+			na, nb = na.next(), nb.next()
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package redblack
+
+import (
+	"cmp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffMerge(t *testing.T) {
+	a := assert.New(t)
+
+	t1, t2 := NewOrderedTree[string, int](), NewOrderedTree[string, int]()
+	for k, v := range map[string]int{"a": 1, "b": 2, "c": 3} {
+		t1.Put(k, v)
+	}
+	for k, v := range map[string]int{"b": 2, "c": 4, "d": 5} {
+		t2.Put(k, v)
+	}
+
+	eq := func(v1, v2 int) bool { return v1 == v2 }
+	a.Equal([]Change[string, int]{
+		{Kind: Removed, Key: "a", OldValue: 1},
+		{Kind: Changed, Key: "c", OldValue: 3, NewValue: 4},
+		{Kind: Added, Key: "d", NewValue: 5},
+	}, Diff(t1, t2, eq))
+	a.Empty(Diff(t1, t1, eq))
+	a.Equal("changed", Changed.String())
+
+	m := Merge(t1, t2, func(_ string, v1, v2 int) int { return v1 + v2 })
+	a.NoError(m.CheckInvariants())
+	a.Equal([]string{"a", "b", "c", "d"}, m.Keys())
+	v, _ := m.Get("c")
+	a.Equal(7, v)
+	v, _ = m.Get("b")
+	a.Equal(4, v)
+}
+
+func TestMergeAugmented(t *testing.T) {
+	a := assert.New(t)
+
+	type sum struct{ value, total int }
+	pool := NewNodePool[int, sum](16)
+	newTree := func(ks ...int) *Tree[int, sum] {
+		tr := NewAugmentedTreeFunc(cmp.Compare[int], func(v, l, r *sum) {
+			v.total = v.value
+			if l != nil {
+				v.total += l.total
+			}
+			if r != nil {
+				v.total += r.total
+			}
+		})
+		tr.pool = pool
+		for _, k := range ks {
+			tr.Put(k, sum{value: k})
+		}
+		return tr
+	}
+
+	m := Merge(newTree(1, 2, 3), newTree(3, 4, 5), func(_ int, v1, v2 sum) sum {
+		return sum{value: v1.value + v2.value}
+	})
+	a.NoError(m.CheckInvariants())
+	a.Same(pool, m.pool)
+	a.Equal(18, m.root.value.total)
+}
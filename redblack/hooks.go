@@ -0,0 +1,33 @@
+package redblack
+
+// Hooks are callbacks invoked after single-key mutations of a tree, i.e. by Put, GetElsePut, Delete
+// and the corresponding cursor operations. Bulk operations such as Split, Join or unmarshaling don't invoke them.
+// Any of the callbacks may be nil.
+type Hooks[K any, V any] struct {
+	OnInsert func(key K, value V)
+	OnUpdate func(key K, oldValue, newValue V)
+	OnDelete func(key K, value V)
+}
+
+// SetHooks sets the callbacks invoked on mutations of the tree. Passing nil removes them.
+func (t *Tree[K, V]) SetHooks(hooks *Hooks[K, V]) {
+	t.hooks = hooks
+}
+
+func (t *Tree[K, V]) inserted(key K, value V) {
+	if t.hooks != nil && t.hooks.OnInsert != nil {
+		t.hooks.OnInsert(key, value)
+	}
+}
+
+func (t *Tree[K, V]) updated(key K, oldValue, newValue V) {
+	if t.hooks != nil && t.hooks.OnUpdate != nil {
+		t.hooks.OnUpdate(key, oldValue, newValue)
+	}
+}
+
+func (t *Tree[K, V]) deleted(key K, value V) {
+	if t.hooks != nil && t.hooks.OnDelete != nil {
+		t.hooks.OnDelete(key, value)
+	}
+}
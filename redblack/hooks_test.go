@@ -0,0 +1,35 @@
+package redblack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHooks(t *testing.T) {
+	a := assert.New(t)
+
+	var log []string
+	tr := NewOrderedTree[string, int]()
+	tr.SetHooks(&Hooks[string, int]{
+		OnInsert: func(k string, v int) { log = append(log, fmt.Sprintf("+%s:%d", k, v)) },
+		OnUpdate: func(k string, o, n int) { log = append(log, fmt.Sprintf("~%s:%d>%d", k, o, n)) },
+		OnDelete: func(k string, v int) { log = append(log, fmt.Sprintf("-%s:%d", k, v)) },
+	})
+	tr.Put("a", 1)
+	tr.Put("a", 2)
+	tr.GetElsePut("b", func() int { return 3 })
+	tr.GetElsePut("b", func() int { return 4 })
+	tr.Delete("a")
+	tr.Delete("z")
+	c := tr.Cursor()
+	c.First()
+	c.SetValue(5)
+	c.Delete()
+	a.Equal([]string{"+a:1", "~a:1>2", "+b:3", "-a:2", "~b:3>5", "-b:5"}, log)
+
+	tr.SetHooks(nil)
+	tr.Put("c", 6)
+	a.Len(log, 6)
+}
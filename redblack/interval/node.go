@@ -0,0 +1,252 @@
+package interval
+
+import "cmp"
+
+// node is a node of a leaf-oriented red-black tree whose leaves hold the intervals in ascending order.
+// Internal nodes always have two children and hold the greatest interval of their left subtree as split key.
+type node[T cmp.Ordered, V any] struct {
+	parent, left, right *node[T, V]
+	red                 bool
+	key                 Interval[T]
+	// the item of a leaf
+	leaf *entry[T, V]
+	// the item with the greatest end point among those routed through the node and not held by any of its ancestors,
+	// nil only if all such items are held higher up
+	slot *entry[T, V]
+}
+
+func (n *node[T, V]) isLeaf() bool {
+	return n.left == nil
+}
+
+func isRed[T cmp.Ordered, V any](n *node[T, V]) bool {
+	return n != nil && n.red
+}
+
+// child returns the child of an internal node towards the leaf of the interval.
+func (n *node[T, V]) child(i Interval[T]) *node[T, V] {
+	if compareIntervals(i, n.key) <= 0 {
+		return n.left
+	}
+	return n.right
+}
+
+// find returns the leaf where the interval is or would be.
+func (n *node[T, V]) find(i Interval[T]) *node[T, V] {
+	for !n.isLeaf() {
+		n = n.child(i)
+	}
+	return n
+}
+
+func (n *node[T, V]) enumerate(yield func(Interval[T], V) bool) bool {
+	for !n.isLeaf() {
+		if !n.left.enumerate(yield) {
+			return false
+		}
+		n = n.right
+	}
+	return yield(n.key, n.leaf.value)
+}
+
+// pushDown places an item routed through the node in its subtree, moving down the items with lesser end points.
+func (n *node[T, V]) pushDown(e *entry[T, V]) {
+	for {
+		if n.slot == nil {
+			n.slot = e
+			return
+		}
+		if e.interval.End > n.slot.interval.End {
+			n.slot, e = e, n.slot
+		}
+		if n.isLeaf() {
+			panic("bad priority search tree")
+		}
+		n = n.child(e.interval)
+	}
+}
+
+// fillSlot fills the empty slot of the node by moving up the items below it.
+func (n *node[T, V]) fillSlot() {
+	for !n.isLeaf() {
+		c := n.left
+		if c.slot == nil || n.right.slot != nil && n.right.slot.interval.End > c.slot.interval.End {
+			c = n.right
+		}
+		if c.slot == nil {
+			return
+		}
+		n.slot, c.slot = c.slot, nil
+		n = c
+	}
+}
+
+// replace puts `m` in the place of `n` in the tree.
+func (t *Tree[T, V]) replace(n, m *node[T, V]) {
+	p := n.parent
+	switch {
+	case p == nil:
+		t.root = m
+	case p.left == n:
+		p.left = m
+	default:
+		p.right = m
+	}
+	m.parent = p
+}
+
+// rotateLeft rotates the node down to the left. The split keys are preserved and
+// the items held by both nodes are placed again, which takes logarithmic time.
+func (t *Tree[T, V]) rotateLeft(x *node[T, V]) {
+	y := x.right
+	ex, ey := x.slot, y.slot
+	x.slot, y.slot = nil, nil
+	x.right = y.left
+	x.right.parent = x
+	t.replace(x, y)
+	y.left, x.parent = x, y
+	t.resettle(x, y, ex, ey)
+}
+
+// rotateRight rotates the node down to the right like rotateLeft.
+func (t *Tree[T, V]) rotateRight(x *node[T, V]) {
+	y := x.left
+	ex, ey := x.slot, y.slot
+	x.slot, y.slot = nil, nil
+	x.left = y.right
+	x.left.parent = x
+	t.replace(x, y)
+	y.right, x.parent = x, y
+	t.resettle(x, y, ex, ey)
+}
+
+// resettle fills the slots emptied by a rotation of `x` below `y` and places their former items again.
+func (t *Tree[T, V]) resettle(x, y *node[T, V], ex, ey *entry[T, V]) {
+	x.fillSlot()
+	y.fillSlot()
+	if ex != nil {
+		y.pushDown(ex)
+	}
+	if ey != nil {
+		y.pushDown(ey)
+	}
+}
+
+func (t *Tree[T, V]) fixInsert(z *node[T, V]) {
+	for isRed(z.parent) {
+		p := z.parent
+		g := p.parent
+		if p == g.left {
+			if u := g.right; isRed(u) {
+				p.red, u.red, g.red = false, false, true
+				z = g
+				continue
+			}
+			if z == p.right {
+				z = p
+				t.rotateLeft(z)
+				p = z.parent
+			}
+			p.red, g.red = false, true
+			t.rotateRight(g)
+		} else {
+			if u := g.left; isRed(u) {
+				p.red, u.red, g.red = false, false, true
+				z = g
+				continue
+			}
+			if z == p.left {
+				z = p
+				t.rotateRight(z)
+				p = z.parent
+			}
+			p.red, g.red = false, true
+			t.rotateLeft(g)
+		}
+	}
+	t.root.red = false
+}
+
+// fixRemove restores the red-black invariants after the removal of a black node from above `x`.
+func (t *Tree[T, V]) fixRemove(x *node[T, V]) {
+	for x != t.root && !x.red {
+		p := x.parent
+		if x == p.left {
+			w := p.right
+			if w.red {
+				w.red, p.red = false, true
+				t.rotateLeft(p)
+				w = p.right
+			}
+			if !isRed(w.left) && !isRed(w.right) {
+				w.red = true
+				x = p
+				continue
+			}
+			if !isRed(w.right) {
+				w.left.red, w.red = false, true
+				t.rotateRight(w)
+				w = p.right
+			}
+			w.red, p.red = p.red, false
+			w.right.red = false
+			t.rotateLeft(p)
+			x = t.root
+		} else {
+			w := p.left
+			if w.red {
+				w.red, p.red = false, true
+				t.rotateRight(p)
+				w = p.left
+			}
+			if !isRed(w.left) && !isRed(w.right) {
+				w.red = true
+				x = p
+				continue
+			}
+			if !isRed(w.left) {
+				w.right.red, w.red = false, true
+				t.rotateLeft(w)
+				w = p.left
+			}
+			w.red, p.red = p.red, false
+			w.left.red = false
+			t.rotateRight(p)
+			x = t.root
+		}
+	}
+	x.red = false
+}
+
+// query enumerates the items overlapping an interval. Every node it visits holds a result,
+// lies on the search path of the query's end point or is a child of such a node.
+type query[T cmp.Ordered, V any] struct {
+	q      Interval[T]
+	yield  func(Interval[T], V) bool
+	visits int
+}
+
+func (q *query[T, V]) visit(n *node[T, V]) bool {
+	for {
+		q.visits++
+		// the items below have lesser end points
+		if n.slot == nil || n.slot.interval.End < q.q.Start {
+			return true
+		}
+		if n.slot.interval.Start <= q.q.End && !q.yield(n.slot.interval, n.slot.value) {
+			return false
+		}
+		if n.isLeaf() {
+			return true
+		}
+		// the intervals in the right subtree start after the split key
+		if n.key.Start > q.q.End {
+			n = n.left
+			continue
+		}
+		if !q.visit(n.left) {
+			return false
+		}
+		n = n.right
+	}
+}
@@ -0,0 +1,188 @@
+package interval
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+)
+
+// Interval is a closed interval.
+type Interval[T cmp.Ordered] struct {
+	Start, End T
+}
+
+// Overlaps returns true if the intervals have at least one point in common.
+func (i Interval[T]) Overlaps(i2 Interval[T]) bool {
+	return i.Start <= i2.End && i2.Start <= i.End
+}
+
+// Contains returns true if the point lies in the interval.
+func (i Interval[T]) Contains(p T) bool {
+	return i.Start <= p && p <= i.End
+}
+
+// String returns the interval in the `[start,end]` notation.
+func (i Interval[T]) String() string {
+	return fmt.Sprintf("[%v,%v]", i.Start, i.End)
+}
+
+func compareIntervals[T cmp.Ordered](i1, i2 Interval[T]) int {
+	if c := cmp.Compare(i1.Start, i2.Start); c != 0 {
+		return c
+	}
+	return cmp.Compare(i1.End, i2.End)
+}
+
+type entry[T cmp.Ordered, V any] struct {
+	interval Interval[T]
+	value    V
+}
+
+// Tree is an interval tree mapping distinct intervals to values.
+// It's a priority search tree laid over a leaf-oriented red-black tree ordered by start and end points,
+// each node of which holds the interval with the greatest end point among those below it not held higher up.
+type Tree[T cmp.Ordered, V any] struct {
+	root *node[T, V]
+	size int
+}
+
+// New creates a new interval tree.
+func New[T cmp.Ordered, V any]() *Tree[T, V] {
+	return new(Tree[T, V])
+}
+
+// Size returns the number of intervals in the tree.
+func (t *Tree[T, V]) Size() int {
+	return t.size
+}
+
+// Insert inserts an interval with its value into the tree or replaces the value for an existing interval.
+// It runs in logarithmic time and panics if the interval is empty.
+func (t *Tree[T, V]) Insert(i Interval[T], value V) (oldValue V, updated bool) {
+	if i.Start > i.End {
+		panic(fmt.Sprintf("bad interval '%v'", i))
+	}
+	e := &entry[T, V]{interval: i, value: value}
+	if t.root == nil {
+		t.root = &node[T, V]{key: i, leaf: e, slot: e}
+		t.size++
+		return
+	}
+	l := t.root.find(i)
+	if c := compareIntervals(i, l.key); c == 0 {
+		oldValue, l.leaf.value = l.leaf.value, value
+		return oldValue, true
+	}
+	// the leaf is replaced by a red node splitting it from the new leaf
+	n := &node[T, V]{red: true}
+	t.replace(l, n)
+	leaf := &node[T, V]{key: i, leaf: e}
+	if compareIntervals(i, l.key) < 0 {
+		n.left, n.right, n.key = leaf, l, i
+	} else {
+		n.left, n.right, n.key = l, leaf, l.key
+	}
+	leaf.parent, l.parent = n, n
+	n.fillSlot()
+	t.root.pushDown(e)
+	t.fixInsert(n)
+	t.size++
+	return
+}
+
+// Get returns the value for the given interval.
+func (t *Tree[T, V]) Get(i Interval[T]) (V, bool) {
+	if t.root != nil {
+		if l := t.root.find(i); compareIntervals(i, l.key) == 0 {
+			return l.leaf.value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// Delete removes an interval from the tree and returns its former value.
+// It runs in logarithmic time.
+func (t *Tree[T, V]) Delete(i Interval[T]) (oldValue V, deleted bool) {
+	if t.root == nil {
+		return
+	}
+	// the internal node whose left subtree ends with the interval, if any, gets a new split key
+	var split *node[T, V]
+	l := t.root
+	for !l.isLeaf() {
+		if compareIntervals(i, l.key) == 0 {
+			split = l
+		}
+		l = l.child(i)
+	}
+	if compareIntervals(i, l.key) != 0 {
+		return
+	}
+	e := l.leaf
+	n := l
+	for n.slot != e {
+		n = n.parent
+	}
+	n.slot = nil
+	n.fillSlot()
+	t.size--
+	p := l.parent
+	if p == nil {
+		t.root = nil
+		return e.value, true
+	}
+	s := p.left
+	if s == l {
+		s = p.right
+	}
+	moved := p.slot
+	t.replace(p, s)
+	if moved != nil {
+		s.pushDown(moved)
+	}
+	if split != nil && split != p {
+		m := split.left
+		for !m.isLeaf() {
+			m = m.right
+		}
+		split.key = m.key
+	}
+	if !p.red {
+		t.fixRemove(s)
+	}
+	return e.value, true
+}
+
+// All returns an iterator over the intervals and their values ordered by start and end points.
+func (t *Tree[T, V]) All() iter.Seq2[Interval[T], V] {
+	return func(yield func(Interval[T], V) bool) {
+		if t.root != nil {
+			t.root.enumerate(yield)
+		}
+	}
+}
+
+// Overlapping returns an iterator over the intervals overlapping the query interval in no particular order.
+// The enumeration takes O(log n + k) time for k results.
+func (t *Tree[T, V]) Overlapping(q Interval[T]) iter.Seq2[Interval[T], V] {
+	return func(yield func(Interval[T], V) bool) {
+		if t.root != nil {
+			(&query[T, V]{q: q, yield: yield}).visit(t.root)
+		}
+	}
+}
+
+// Stabbing returns an iterator over the intervals containing the point in no particular order.
+func (t *Tree[T, V]) Stabbing(p T) iter.Seq2[Interval[T], V] {
+	return t.Overlapping(Interval[T]{Start: p, End: p})
+}
+
+// Any returns true if any interval in the tree overlaps the query interval.
+// It runs in logarithmic time.
+func (t *Tree[T, V]) Any(q Interval[T]) bool {
+	for range t.Overlapping(q) {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,192 @@
+package interval
+
+import (
+	"math/bits"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverlapping(t *testing.T) {
+	a := assert.New(t)
+
+	tr := New[int, int]()
+	var all []Interval[int]
+	for i := 0; i < 2_000; i++ {
+		s := rand.Intn(1_000)
+		iv := Interval[int]{Start: s, End: s + rand.Intn(50)}
+		if rand.Intn(4) == 0 && len(all) > 0 {
+			j := rand.Intn(len(all))
+			_, ok := tr.Delete(all[j])
+			a.True(ok)
+			all = append(all[:j], all[j+1:]...)
+			continue
+		}
+		if _, updated := tr.Insert(iv, i); !updated {
+			all = append(all, iv)
+		}
+	}
+	a.Equal(len(all), tr.Size())
+
+	for i := 0; i < 200; i++ {
+		s := rand.Intn(1_100)
+		q := Interval[int]{Start: s, End: s + rand.Intn(20)}
+		want := make(map[Interval[int]]struct{})
+		for _, iv := range all {
+			if iv.Overlaps(q) {
+				want[iv] = struct{}{}
+			}
+		}
+		got := make(map[Interval[int]]struct{})
+		for iv := range tr.Overlapping(q) {
+			got[iv] = struct{}{}
+		}
+		a.Equal(want, got)
+		a.Equal(len(want) > 0, tr.Any(q))
+
+		var stabbed int
+		for iv := range tr.Stabbing(s) {
+			a.True(iv.Contains(s))
+			stabbed++
+		}
+		var want2 int
+		for _, iv := range all {
+			if iv.Contains(s) {
+				want2++
+			}
+		}
+		a.Equal(want2, stabbed)
+	}
+
+	a.Panics(func() { tr.Insert(Interval[int]{Start: 2, End: 1}, 0) })
+}
+
+// check verifies the red-black and priority search tree invariants and returns the items in order.
+func (t *Tree[T, V]) check(a *assert.Assertions) []Interval[T] {
+	if t.root == nil {
+		a.Zero(t.size)
+		return nil
+	}
+	a.False(t.root.red)
+	a.Nil(t.root.parent)
+	var leaves []Interval[T]
+	held := make(map[*entry[T, V]]struct{})
+	var walk func(n *node[T, V]) int
+	walk = func(n *node[T, V]) int {
+		if n.slot != nil {
+			held[n.slot] = struct{}{}
+			// the item is routed through the node
+			l := n.find(n.slot.interval)
+			a.Equal(n.slot, l.leaf)
+			for _, c := range []*node[T, V]{n.left, n.right} {
+				if c != nil && c.slot != nil {
+					a.LessOrEqual(c.slot.interval.End, n.slot.interval.End)
+				}
+			}
+		} else if !n.isLeaf() {
+			a.Nil(n.left.slot)
+			a.Nil(n.right.slot)
+		}
+		if n.isLeaf() {
+			a.Nil(n.right)
+			a.False(n.red)
+			a.Equal(n.key, n.leaf.interval)
+			leaves = append(leaves, n.key)
+			return 1
+		}
+		a.NotNil(n.right)
+		a.Same(n, n.left.parent)
+		a.Same(n, n.right.parent)
+		if n.red {
+			a.False(isRed(n.left))
+			a.False(isRed(n.right))
+		}
+		lh := walk(n.left)
+		a.Equal(n.key, leaves[len(leaves)-1])
+		rh := walk(n.right)
+		a.Equal(lh, rh)
+		if !n.red {
+			lh++
+		}
+		return lh
+	}
+	walk(t.root)
+	a.Len(leaves, t.size)
+	a.Len(held, t.size)
+	for i := 1; i < len(leaves); i++ {
+		a.Negative(compareIntervals(leaves[i-1], leaves[i]))
+	}
+	return leaves
+}
+
+func TestInvariants(t *testing.T) {
+	a := assert.New(t)
+
+	tr := New[int, int]()
+	m := make(map[Interval[int]]int)
+	for i := 0; i < 5_000; i++ {
+		s := rand.Intn(300)
+		iv := Interval[int]{Start: s, End: s + rand.Intn(30)}
+		if rand.Intn(3) == 0 {
+			v, ok := tr.Delete(iv)
+			mv, mok := m[iv]
+			a.Equal(mok, ok)
+			a.Equal(mv, v)
+			delete(m, iv)
+		} else {
+			v, ok := tr.Insert(iv, i)
+			mv, mok := m[iv]
+			a.Equal(mok, ok)
+			a.Equal(mv, v)
+			m[iv] = i
+		}
+		if i%500 == 0 {
+			tr.check(a)
+		}
+	}
+	all := tr.check(a)
+	a.Len(all, len(m))
+	var seen []Interval[int]
+	for iv, v := range tr.All() {
+		a.Equal(m[iv], v)
+		seen = append(seen, iv)
+	}
+	a.Equal(all, seen)
+	for iv := range m {
+		_, ok := tr.Delete(iv)
+		a.True(ok)
+	}
+	tr.check(a)
+	a.Zero(tr.Size())
+	a.False(tr.Any(Interval[int]{Start: 0, End: 1_000}))
+}
+
+func TestOverlappingVisits(t *testing.T) {
+	a := assert.New(t)
+
+	const n = 1 << 16
+	tr := New[int, int]()
+	for i := 0; i < n; i++ {
+		s := rand.Intn(n)
+		tr.Insert(Interval[int]{Start: s, End: s + rand.Intn(8)}, i)
+	}
+	// nested intervals whose end points are unrelated to their start points
+	for i := 0; i < 1_000; i++ {
+		s := rand.Intn(n)
+		tr.Insert(Interval[int]{Start: s, End: s + rand.Intn(n)}, i)
+	}
+	logN := bits.Len(uint(n))
+	for _, width := range []int{0, 16, 256, 4_096} {
+		s := rand.Intn(n - width)
+		q := &query[int, int]{q: Interval[int]{Start: s, End: s + width}}
+		var k int
+		q.yield = func(Interval[int], int) bool {
+			k++
+			return true
+		}
+		q.visit(tr.root)
+		// the search path of the query's end point, the results and their children
+		a.LessOrEqual(q.visits, 2*(2*logN+1)+3*k)
+	}
+}
@@ -0,0 +1,175 @@
+package redblack
+
+import (
+	"iter"
+	"slices"
+
+	"github.com/fealsamh/datastructures/constraints"
+)
+
+// MultiMap is an ordered map allowing multiple values per key.
+// The values for a key are kept in insertion order.
+type MultiMap[K any, V any] struct {
+	tree *Tree[K, []V]
+	size int
+}
+
+// NewMultiMap creates a new red-black multimap.
+func NewMultiMap[K constraints.Comparable[K], V any]() *MultiMap[K, V] {
+	return NewMultiMapFunc[K, V](compareMethod[K])
+}
+
+// NewMultiMapFunc creates a new red-black multimap whose keys are ordered by a comparison function.
+func NewMultiMapFunc[K any, V any](compare func(K, K) int) *MultiMap[K, V] {
+	return &MultiMap[K, V]{tree: NewTreeFunc[K, []V](compare)}
+}
+
+// Size returns the number of entries in the multimap.
+func (m *MultiMap[K, V]) Size() int {
+	return m.size
+}
+
+// Keys returns the distinct keys in the multimap.
+func (m *MultiMap[K, V]) Keys() []K {
+	return m.tree.Keys()
+}
+
+// Add adds a value for the key.
+func (m *MultiMap[K, V]) Add(key K, value V) {
+	vs, _ := m.tree.Get(key)
+	m.tree.Put(key, append(vs, value))
+	m.size++
+}
+
+// Count returns the number of values for the key.
+func (m *MultiMap[K, V]) Count(key K) int {
+	vs, _ := m.tree.Get(key)
+	return len(vs)
+}
+
+// ValuesFor returns a copy of the values for the key in insertion order.
+func (m *MultiMap[K, V]) ValuesFor(key K) []V {
+	vs, _ := m.tree.Get(key)
+	return slices.Clone(vs)
+}
+
+// RemoveOne removes the earliest added value for the key.
+func (m *MultiMap[K, V]) RemoveOne(key K) (value V, removed bool) {
+	vs, ok := m.tree.Get(key)
+	if !ok {
+		return
+	}
+	value = vs[0]
+	var zero V
+	vs[0] = zero
+	if len(vs) == 1 {
+		m.tree.Delete(key)
+	} else {
+		m.tree.Put(key, vs[1:])
+	}
+	m.size--
+	return value, true
+}
+
+// RemoveAll removes all the values for the key and returns them.
+func (m *MultiMap[K, V]) RemoveAll(key K) []V {
+	vs, _ := m.tree.Delete(key)
+	m.size -= len(vs)
+	return vs
+}
+
+// All returns an iterator over all the entries in ascending key order.
+// The values for a key are yielded in insertion order.
+func (m *MultiMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.tree.Enumerate(func(k K, vs []V) bool {
+			for _, v := range vs {
+				if !yield(k, v) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+}
+
+// MultiSet is an ordered set allowing multiple occurrences of an element.
+type MultiSet[K any] struct {
+	tree *Tree[K, int]
+	size int
+}
+
+// NewMultiSet creates a new red-black multiset.
+func NewMultiSet[K constraints.Comparable[K]]() *MultiSet[K] {
+	return NewMultiSetFunc[K](compareMethod[K])
+}
+
+// NewMultiSetFunc creates a new red-black multiset whose elements are ordered by a comparison function.
+func NewMultiSetFunc[K any](compare func(K, K) int) *MultiSet[K] {
+	return &MultiSet[K]{tree: NewTreeFunc[K, int](compare)}
+}
+
+// Size returns the number of occurrences of all the elements in the multiset.
+func (s *MultiSet[K]) Size() int {
+	return s.size
+}
+
+// Values returns the distinct elements of the multiset.
+func (s *MultiSet[K]) Values() []K {
+	return s.tree.Keys()
+}
+
+// Add adds an occurrence of the element and returns its new count.
+func (s *MultiSet[K]) Add(key K) int {
+	c, _ := s.tree.Get(key)
+	s.tree.Put(key, c+1)
+	s.size++
+	return c + 1
+}
+
+// Count returns the number of occurrences of the element.
+func (s *MultiSet[K]) Count(key K) int {
+	c, _ := s.tree.Get(key)
+	return c
+}
+
+// RemoveOne removes an occurrence of the element.
+func (s *MultiSet[K]) RemoveOne(key K) bool {
+	c, ok := s.tree.Get(key)
+	if !ok {
+		return false
+	}
+	if c == 1 {
+		s.tree.Delete(key)
+	} else {
+		s.tree.Put(key, c-1)
+	}
+	s.size--
+	return true
+}
+
+// RemoveAll removes all the occurrences of the element and returns their number.
+func (s *MultiSet[K]) RemoveAll(key K) int {
+	c, _ := s.tree.Delete(key)
+	s.size -= c
+	return c
+}
+
+// All returns an iterator over all the occurrences of the elements in ascending order.
+func (s *MultiSet[K]) All() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		s.tree.Enumerate(func(k K, c int) bool {
+			for i := 0; i < c; i++ {
+				if !yield(k) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+}
+
+// Counts returns an iterator over the distinct elements and their counts in ascending order.
+func (s *MultiSet[K]) Counts() iter.Seq2[K, int] {
+	return s.tree.All()
+}
@@ -0,0 +1,66 @@
+package redblack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiMap(t *testing.T) {
+	a := assert.New(t)
+
+	m := NewMultiMap[compString, int]()
+	m.Add("b", 1)
+	m.Add("a", 2)
+	m.Add("b", 3)
+	m.Add("b", 4)
+	a.Equal(4, m.Size())
+	a.Equal(3, m.Count("b"))
+	a.Equal([]int{1, 3, 4}, m.ValuesFor("b"))
+	a.Nil(m.ValuesFor("c"))
+
+	var ks []compString
+	var vs []int
+	for k, v := range m.All() {
+		ks = append(ks, k)
+		vs = append(vs, v)
+	}
+	a.Equal([]compString{"a", "b", "b", "b"}, ks)
+	a.Equal([]int{2, 1, 3, 4}, vs)
+
+	v, ok := m.RemoveOne("b")
+	a.True(ok)
+	a.Equal(1, v)
+	_, ok = m.RemoveOne("c")
+	a.False(ok)
+	a.Equal([]int{3, 4}, m.RemoveAll("b"))
+	v, ok = m.RemoveOne("a")
+	a.True(ok)
+	a.Equal(2, v)
+	a.Equal(0, m.Size())
+	a.Nil(m.Keys())
+}
+
+func TestMultiSet(t *testing.T) {
+	a := assert.New(t)
+
+	s := NewMultiSetFunc(func(i1, i2 int) int { return i1 - i2 })
+	a.Equal(1, s.Add(2))
+	a.Equal(1, s.Add(1))
+	a.Equal(2, s.Add(2))
+	a.Equal(3, s.Size())
+	a.Equal(2, s.Count(2))
+
+	var es []int
+	for e := range s.All() {
+		es = append(es, e)
+	}
+	a.Equal([]int{1, 2, 2}, es)
+
+	a.True(s.RemoveOne(2))
+	a.Equal(1, s.Count(2))
+	a.False(s.RemoveOne(3))
+	a.Equal(1, s.RemoveAll(1))
+	a.Equal([]int{2}, s.Values())
+	a.Equal(1, s.Size())
+}
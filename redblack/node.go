@@ -2,8 +2,7 @@ package redblack
 
 import (
 	"fmt"
-
-	"github.com/fealsamh/datastructures/constraints"
+	"strings"
 )
 
 type color byte
@@ -21,140 +20,357 @@ const (
 	right
 )
 
-type node[K constraints.Comparable[K], V any] struct {
+type node[K any, V any] struct {
 	key    K
 	value  V
 	color  color
 	parent *node[K, V]
-	tree   *Tree[K, V]
 	left   *node[K, V]
 	right  *node[K, V]
+	count  int
+}
+
+func (n *node[K, V]) check(compare func(K, K) int) bool {
+	// an explicit stack is used since the parent links being checked can't be relied upon
+	stack := []*node[K, V]{n}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if n.left != nil {
+			if compare(n.left.key, n.key) >= 0 || n.left.parent != n {
+				return false
+			}
+			stack = append(stack, n.left)
+		}
+		if n.right != nil {
+			if compare(n.key, n.right.key) >= 0 || n.right.parent != n {
+				return false
+			}
+			stack = append(stack, n.right)
+		}
+	}
+	return true
 }
 
-func (n *node[K, V]) check() bool {
+// checkInvariants verifies the red-black invariants of the subtree and returns its black height.
+func (n *node[K, V]) checkInvariants(t *Tree[K, V]) (int, error) {
+	if n.color == red && (colorOf(n.left) == red || colorOf(n.right) == red) {
+		return 0, fmt.Errorf("red node '%v' has a red child", n.key)
+	}
+	var lh, rh int
 	if n.left != nil {
-		if n.left.key.Compare(n.key) >= 0 {
-			return false
+		if t.comparator()(n.left.key, n.key) >= 0 {
+			return 0, fmt.Errorf("left child '%v' of node '%v' isn't less than it", n.left.key, n.key)
 		}
 		if n.left.parent != n {
-			return false
+			return 0, fmt.Errorf("left child '%v' of node '%v' doesn't point to its parent", n.left.key, n.key)
 		}
-		if !n.left.check() {
-			return false
+		h, err := n.left.checkInvariants(t)
+		if err != nil {
+			return 0, err
 		}
+		lh = h
 	}
 	if n.right != nil {
-		if n.key.Compare(n.right.key) >= 0 {
-			return false
+		if t.comparator()(n.key, n.right.key) >= 0 {
+			return 0, fmt.Errorf("right child '%v' of node '%v' isn't greater than it", n.right.key, n.key)
 		}
 		if n.right.parent != n {
-			return false
+			return 0, fmt.Errorf("right child '%v' of node '%v' doesn't point to its parent", n.right.key, n.key)
 		}
-		if !n.right.check() {
-			return false
+		h, err := n.right.checkInvariants(t)
+		if err != nil {
+			return 0, err
 		}
+		rh = h
 	}
-	return true
+	if lh != rh {
+		return 0, fmt.Errorf("node '%v' has black heights %d and %d in its subtrees", n.key, lh, rh)
+	}
+	if c := 1 + n.left.size() + n.right.size(); n.count != c {
+		return 0, fmt.Errorf("node '%v' has size %d instead of %d", n.key, n.count, c)
+	}
+	if n.color == black {
+		lh++
+	}
+	return lh, nil
 }
 
 func (n *node[K, V]) depth() int {
-	var ld, rd int
-	if n.left != nil {
-		ld = n.left.depth()
+	type frame struct {
+		n     *node[K, V]
+		depth int
 	}
-	if n.right != nil {
-		rd = n.right.depth()
-	}
-	if ld > rd {
-		return ld + 1
+	var d int
+	stack := []frame{{n, 1}}
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		d = max(d, f.depth)
+		if f.n.left != nil {
+			stack = append(stack, frame{f.n.left, f.depth + 1})
+		}
+		if f.n.right != nil {
+			stack = append(stack, frame{f.n.right, f.depth + 1})
+		}
 	}
-	return rd + 1
+	return d
 }
 
 func (n *node[K, V]) size() int {
-	r := 1
-	if n.left != nil {
-		r += n.left.size()
+	if n == nil {
+		return 0
 	}
-	if n.right != nil {
-		r += n.right.size()
+	return n.count
+}
+
+func (n *node[K, V]) update(t *Tree[K, V]) {
+	n.count = 1 + n.left.size() + n.right.size()
+	if augment := t.augment; augment != nil {
+		var l, r *V
+		if n.left != nil {
+			l = &n.left.value
+		}
+		if n.right != nil {
+			r = &n.right.value
+		}
+		augment(&n.value, l, r)
+	}
+}
+
+func (n *node[K, V]) updatePath(t *Tree[K, V]) {
+	for ; n != nil; n = n.parent {
+		n.update(t)
+	}
+}
+
+func (n *node[K, V]) attach(t *Tree[K, V], key K, value V, dir direction) {
+	l := t.newNode(key, value, red, n)
+	if dir == left {
+		n.left = l
+	} else {
+		n.right = l
+	}
+	l.updatePath(t)
+	l.ensureInvariants(t)
+}
+
+func (n *node[K, V]) rank(compare func(K, K) int, key K) int {
+	var r int
+	for n != nil {
+		c := compare(key, n.key)
+		switch {
+		case c == 0:
+			return r + n.left.size()
+		case c < 0:
+			n = n.left
+		default:
+			r += n.left.size() + 1
+			n = n.right
+		}
 	}
 	return r
 }
 
-func (n *node[K, V]) keys() []K {
-	var ks []K
-	if n.left != nil {
-		ks = n.left.keys()
+func (n *node[K, V]) selectAt(i int) *node[K, V] {
+	for n != nil {
+		ls := n.left.size()
+		switch {
+		case i == ls:
+			return n
+		case i < ls:
+			n = n.left
+		default:
+			i -= ls + 1
+			n = n.right
+		}
 	}
-	ks = append(ks, n.key)
-	if n.right != nil {
-		ks = append(ks, n.right.keys()...)
+	return nil
+}
+
+// The traversals below must be called on the root, they step between nodes
+// using parent links in amortized constant time or an explicit stack.
+
+func (n *node[K, V]) keys() []K {
+	ks := make([]K, 0, n.count)
+	for m := n.min(); m != nil; m = m.next() {
+		ks = append(ks, m.key)
 	}
 	return ks
 }
 
+func (n *node[K, V]) values() []V {
+	vs := make([]V, 0, n.count)
+	for m := n.min(); m != nil; m = m.next() {
+		vs = append(vs, m.value)
+	}
+	return vs
+}
+
 func (n *node[K, V]) enumerate(f func(K, V) bool) bool {
-	if n.left != nil {
-		if !n.left.enumerate(f) {
+	// a red-black tree with 2^64 nodes is at most 128 levels deep
+	var stack [128]*node[K, V]
+	sp := 0
+	for m := n; ; m = m.right {
+		for ; m != nil; m = m.left {
+			stack[sp] = m
+			sp++
+		}
+		if sp == 0 {
+			return true
+		}
+		sp--
+		m = stack[sp]
+		if !f(m.key, m.value) {
 			return false
 		}
 	}
-	if !f(n.key, n.value) {
-		return false
-	}
-	if n.right != nil {
-		if !n.right.enumerate(f) {
+}
+
+func (n *node[K, V]) enumerateBackward(f func(K, V) bool) bool {
+	for m := n.max(); m != nil; m = m.prev() {
+		if !f(m.key, m.value) {
 			return false
 		}
 	}
 	return true
 }
 
+func (n *node[K, V]) enumeratePruned(enter, f func(K, V) bool) bool {
+	var stack []*node[K, V]
+	m := n
+	for {
+		for m != nil && enter(m.key, m.value) {
+			stack = append(stack, m)
+			m = m.left
+		}
+		if len(stack) == 0 {
+			return true
+		}
+		m = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !f(m.key, m.value) {
+			return false
+		}
+		m = m.right
+	}
+}
+
 func (n *node[K, V]) minKey() *K {
-	if n.left != nil {
-		return n.left.minKey()
+	return &n.min().key
+}
+
+func (n *node[K, V]) maxKey() *K {
+	return &n.max().key
+}
+
+func (n *node[K, V]) enumerateRange(compare func(K, K) int, lo, hi K, loInclusive, hiInclusive bool, f func(K, V) bool) bool {
+	for m := n.ceiling(compare, lo, loInclusive); m != nil; m = m.next() {
+		if c := compare(m.key, hi); c > 0 || c == 0 && !hiInclusive {
+			break
+		}
+		if !f(m.key, m.value) {
+			return false
+		}
 	}
-	return &n.key
+	return true
 }
 
-func (n *node[K, V]) str() string {
-	var s string
-	if n.left != nil {
-		s += "(" + n.left.str() + ") "
+// floor returns the node with the greatest key less than (or equal to if inclusive) the given key.
+func (n *node[K, V]) floor(compare func(K, K) int, key K, inclusive bool) *node[K, V] {
+	var r *node[K, V]
+	for n != nil {
+		c := compare(key, n.key)
+		switch {
+		case c == 0 && inclusive:
+			return n
+		case c > 0:
+			r, n = n, n.right
+		default:
+			n = n.left
+		}
 	}
-	s += fmt.Sprintf("%v:%v", n.key, n.value)
-	if n.color == black {
-		s += "/B"
-	} else {
-		s += "/R"
+	return r
+}
+
+// ceiling returns the node with the least key greater than (or equal to if inclusive) the given key.
+func (n *node[K, V]) ceiling(compare func(K, K) int, key K, inclusive bool) *node[K, V] {
+	var r *node[K, V]
+	for n != nil {
+		c := compare(key, n.key)
+		switch {
+		case c == 0 && inclusive:
+			return n
+		case c < 0:
+			r, n = n, n.left
+		default:
+			n = n.right
+		}
 	}
-	if n.right != nil {
-		s += " (" + n.right.str() + ")"
+	return r
+}
+
+func (n *node[K, V]) item() (key K, value V, found bool) {
+	if n == nil {
+		return
 	}
-	return s
+	return n.key, n.value, true
 }
 
-func (n *node[K, V]) find(key K) (*node[K, V], direction) {
-	c := key.Compare(n.key)
-	switch {
-	case c == 0:
-		return n, exact
-	case c < 0:
-		if n.left == nil {
-			return n, left
+func (n *node[K, V]) str() string {
+	// the stack holds either nodes to be expanded or literal strings
+	type item struct {
+		n *node[K, V]
+		s string
+	}
+	var b strings.Builder
+	stack := []item{{n: n}}
+	for len(stack) > 0 {
+		it := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if it.n == nil {
+			b.WriteString(it.s)
+			continue
+		}
+		n := it.n
+		if n.right != nil {
+			stack = append(stack, item{s: ")"}, item{n: n.right}, item{s: " ("})
+		}
+		label := fmt.Sprintf("%v:%v", n.key, n.value)
+		if n.color == black {
+			label += "/B"
+		} else {
+			label += "/R"
 		}
-		return n.left.find(key)
-	case c > 0:
-		if n.right == nil {
-			return n, right
+		stack = append(stack, item{s: label})
+		if n.left != nil {
+			stack = append(stack, item{s: ") "}, item{n: n.left}, item{s: "("})
 		}
-		return n.right.find(key)
 	}
-	panic("bad red-black node")
+	return b.String()
 }
 
-func (n *node[K, V]) rotateRight() {
+func (n *node[K, V]) find(compare func(K, K) int, key K) (*node[K, V], direction) {
+	for {
+		c := compare(key, n.key)
+		switch {
+		case c == 0:
+			return n, exact
+		case c < 0:
+			if n.left == nil {
+				return n, left
+			}
+			n = n.left
+		default:
+			if n.right == nil {
+				return n, right
+			}
+			n = n.right
+		}
+	}
+}
+
+func (n *node[K, V]) rotateRight(t *Tree[K, V]) {
 	p := n.parent
 	pp := p.parent
 	a, b, c := n.left, n.right, p.right
@@ -168,7 +384,7 @@ func (n *node[K, V]) rotateRight() {
 			panic("bad red-black node")
 		}
 	} else {
-		n.tree.root = n
+		t.root = n
 	}
 	n.parent, p.parent = pp, n
 	n.left, n.right = a, p
@@ -182,9 +398,11 @@ func (n *node[K, V]) rotateRight() {
 	if c != nil {
 		c.parent = p
 	}
+	p.update(t)
+	n.update(t)
 }
 
-func (n *node[K, V]) rotateLeft() {
+func (n *node[K, V]) rotateLeft(t *Tree[K, V]) {
 	p := n.parent
 	pp := p.parent
 	a, b, c := p.left, n.left, n.right
@@ -198,7 +416,7 @@ func (n *node[K, V]) rotateLeft() {
 			panic("bad red-black node")
 		}
 	} else {
-		n.tree.root = n
+		t.root = n
 	}
 	n.parent, p.parent = pp, n
 	n.left, n.right = p, c
@@ -212,14 +430,16 @@ func (n *node[K, V]) rotateLeft() {
 	if b != nil {
 		b.parent = p
 	}
+	p.update(t)
+	n.update(t)
 }
 
-func (n *node[K, V]) rotate() {
+func (n *node[K, V]) rotate(t *Tree[K, V]) {
 	switch n.dir() {
 	case right:
-		n.rotateLeft()
+		n.rotateLeft(t)
 	case left:
-		n.rotateRight()
+		n.rotateRight(t)
 	}
 }
 
@@ -245,30 +465,233 @@ func (n *node[K, V]) brother() *node[K, V] {
 	panic("bad red-black node")
 }
 
-func (n *node[K, V]) ensureInvariants() {
+// ensureInvariants restores the invariants after `n` has been colored red
+// and returns true if the black height of the tree has grown.
+func (n *node[K, V]) ensureInvariants(t *Tree[K, V]) bool {
 	p := n.parent
 	if p == nil {
+		grown := n.color == red
 		n.color = black
-		return
+		return grown
 	}
 	if p.color == black {
-		return
+		return false
 	}
 	pp := p.parent
 	if pp != nil && pp.color == black {
 		u := p.brother()
 		if u != nil && u.color == red {
 			p.color, pp.color, u.color = black, red, black
-			pp.ensureInvariants()
+			return pp.ensureInvariants(t)
+		}
+		if n.dir() == p.dir() {
+			p.rotate(t)
+			p.color, pp.color = black, red
 		} else {
-			if n.dir() == p.dir() {
-				p.rotate()
-				p.color, pp.color = black, red
-			} else {
-				n.rotate()
-				n.rotate()
-				n.color, pp.color = black, red
+			n.rotate(t)
+			n.rotate(t)
+			n.color, pp.color = black, red
+		}
+	}
+	return false
+}
+
+func (n *node[K, V]) min() *node[K, V] {
+	for n.left != nil {
+		n = n.left
+	}
+	return n
+}
+
+func (n *node[K, V]) max() *node[K, V] {
+	for n.right != nil {
+		n = n.right
+	}
+	return n
+}
+
+func (n *node[K, V]) next() *node[K, V] {
+	if n.right != nil {
+		return n.right.min()
+	}
+	for n.parent != nil && n.parent.right == n {
+		n = n.parent
+	}
+	return n.parent
+}
+
+func (n *node[K, V]) prev() *node[K, V] {
+	if n.left != nil {
+		return n.left.max()
+	}
+	for n.parent != nil && n.parent.left == n {
+		n = n.parent
+	}
+	return n.parent
+}
+
+func (n *node[K, V]) transplant(t *Tree[K, V], m *node[K, V]) {
+	p := n.parent
+	switch {
+	case p == nil:
+		t.root = m
+	case p.left == n:
+		p.left = m
+	default:
+		p.right = m
+	}
+	if m != nil {
+		m.parent = p
+	}
+}
+
+func (n *node[K, V]) remove(t *Tree[K, V]) {
+	var x, xParent *node[K, V]
+	removed := n.color
+	switch {
+	case n.left == nil:
+		x, xParent = n.right, n.parent
+		n.transplant(t, n.right)
+	case n.right == nil:
+		x, xParent = n.left, n.parent
+		n.transplant(t, n.left)
+	default:
+		y := n.right.min()
+		removed = y.color
+		x = y.right
+		if y.parent == n {
+			xParent = y
+		} else {
+			xParent = y.parent
+			y.transplant(t, y.right)
+			y.right = n.right
+			y.right.parent = y
+		}
+		n.transplant(t, y)
+		y.left = n.left
+		y.left.parent = y
+		y.color = n.color
+	}
+	xParent.updatePath(t)
+	n.parent, n.left, n.right = nil, nil, nil
+	if removed == black {
+		t.fixRemove(x, xParent)
+	}
+}
+
+func colorOf[K any, V any](n *node[K, V]) color {
+	if n == nil {
+		return black
+	}
+	return n.color
+}
+
+func (t *Tree[K, V]) fixRemove(x, p *node[K, V]) {
+	for x != t.root && colorOf(x) == black {
+		if x == p.left {
+			w := p.right
+			if w.color == red {
+				w.color, p.color = black, red
+				w.rotateLeft(t)
+				w = p.right
+			}
+			if colorOf(w.left) == black && colorOf(w.right) == black {
+				w.color = red
+				x, p = p, p.parent
+				continue
+			}
+			if colorOf(w.right) == black {
+				w.left.color, w.color = black, red
+				w.left.rotateRight(t)
+				w = p.right
+			}
+			w.color, p.color = p.color, black
+			w.right.color = black
+			w.rotateLeft(t)
+			x = t.root
+		} else {
+			w := p.left
+			if w.color == red {
+				w.color, p.color = black, red
+				w.rotateRight(t)
+				w = p.left
+			}
+			if colorOf(w.left) == black && colorOf(w.right) == black {
+				w.color = red
+				x, p = p, p.parent
+				continue
+			}
+			if colorOf(w.left) == black {
+				w.right.color, w.color = black, red
+				w.right.rotateLeft(t)
+				w = p.left
 			}
+			w.color, p.color = p.color, black
+			w.left.color = black
+			w.rotateRight(t)
+			x = t.root
 		}
 	}
+	if x != nil {
+		x.color = black
+	}
+}
+
+// buildSorted builds a balanced subtree from sorted keys, coloring the nodes on the incomplete bottom level red.
+// If `values` is nil, the nodes get zero values.
+func buildSorted[K any, V any](t *Tree[K, V], keys []K, values []V, depth, redDepth int) *node[K, V] {
+	if len(keys) == 0 {
+		return nil
+	}
+	mid := len(keys) / 2
+	var value V
+	if values != nil {
+		value = values[mid]
+	}
+	n := t.newNode(keys[mid], value, black, nil)
+	if depth == redDepth {
+		n.color = red
+	}
+	var lv, rv []V
+	if values != nil {
+		lv, rv = values[:mid], values[mid+1:]
+	}
+	if n.left = buildSorted(t, keys[:mid], lv, depth+1, redDepth); n.left != nil {
+		n.left.parent = n
+	}
+	if n.right = buildSorted(t, keys[mid+1:], rv, depth+1, redDepth); n.right != nil {
+		n.right.parent = n
+	}
+	n.update(t)
+	return n
+}
+
+// detach detaches the node from its parent, making it a black root.
+func (n *node[K, V]) detach() *node[K, V] {
+	if n != nil {
+		n.parent, n.color = nil, black
+	}
+	return n
+}
+
+func (n *node[K, V]) blackHeight() int {
+	var h int
+	for ; n != nil; n = n.left {
+		if n.color == black {
+			h++
+		}
+	}
+	return h
+}
+
+func (n *node[K, V]) clone(t *Tree[K, V], parent *node[K, V]) *node[K, V] {
+	c := t.newNode(n.key, n.value, n.color, parent)
+	c.count = n.count
+	if n.left != nil {
+		c.left = n.left.clone(t, c)
+	}
+	if n.right != nil {
+		c.right = n.right.clone(t, c)
+	}
+	return c
 }
@@ -0,0 +1,238 @@
+package persistent
+
+import "github.com/fealsamh/datastructures/constraints"
+
+type color byte
+
+const (
+	black color = iota
+	red
+)
+
+type node[K constraints.Comparable[K], V any] struct {
+	key   K
+	value V
+	color color
+	left  *node[K, V]
+	right *node[K, V]
+}
+
+// Tree is a persistent red-black tree.
+// Its operations never modify an existing version, they return a new one sharing structure with the old one.
+// The zero value is an empty tree.
+type Tree[K constraints.Comparable[K], V any] struct {
+	root *node[K, V]
+	size int
+}
+
+// NewTree creates a new persistent red-black tree.
+func NewTree[K constraints.Comparable[K], V any]() *Tree[K, V] { return new(Tree[K, V]) }
+
+// Size returns the size of the tree.
+func (t *Tree[K, V]) Size() int {
+	return t.size
+}
+
+// Get returns the value for the given key.
+func (t *Tree[K, V]) Get(key K) (retValue V, found bool) {
+	n := t.root
+	for n != nil {
+		c := key.Compare(n.key)
+		switch {
+		case c == 0:
+			return n.value, true
+		case c < 0:
+			n = n.left
+		default:
+			n = n.right
+		}
+	}
+	return
+}
+
+// Keys returns the keys of the items in the tree.
+func (t *Tree[K, V]) Keys() []K {
+	ks := make([]K, 0, t.size)
+	t.Enumerate(func(k K, _ V) bool {
+		ks = append(ks, k)
+		return true
+	})
+	return ks
+}
+
+// Enumerate enumerates all the items in the tree.
+func (t *Tree[K, V]) Enumerate(f func(K, V) bool) bool {
+	return t.root.enumerate(f)
+}
+
+// MinKey returns the minimum key in the tree or nil if the tree is empty.
+func (t *Tree[K, V]) MinKey() *K {
+	n := t.root
+	if n == nil {
+		return nil
+	}
+	for n.left != nil {
+		n = n.left
+	}
+	return &n.key
+}
+
+// Put returns a new version of the tree with the key-value pair inserted or its value replaced.
+func (t *Tree[K, V]) Put(key K, value V) *Tree[K, V] {
+	root, added := t.root.put(key, value)
+	root = root.blacken()
+	size := t.size
+	if added {
+		size++
+	}
+	return &Tree[K, V]{root: root, size: size}
+}
+
+// Delete returns a new version of the tree without the key.
+// The same version is returned if the key isn't found.
+func (t *Tree[K, V]) Delete(key K) *Tree[K, V] {
+	if _, ok := t.Get(key); !ok {
+		return t
+	}
+	root := t.root.del(key)
+	if root != nil {
+		root = root.blacken()
+	}
+	return &Tree[K, V]{root: root, size: t.size - 1}
+}
+
+func (n *node[K, V]) enumerate(f func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+	return n.left.enumerate(f) && f(n.key, n.value) && n.right.enumerate(f)
+}
+
+func (n *node[K, V]) isRed() bool {
+	return n != nil && n.color == red
+}
+
+func (n *node[K, V]) isBlack() bool {
+	return n != nil && n.color == black
+}
+
+func (n *node[K, V]) with(c color, l, r *node[K, V]) *node[K, V] {
+	return &node[K, V]{key: n.key, value: n.value, color: c, left: l, right: r}
+}
+
+func (n *node[K, V]) blacken() *node[K, V] {
+	if n.color == black {
+		return n
+	}
+	return n.with(black, n.left, n.right)
+}
+
+func (n *node[K, V]) redden() *node[K, V] {
+	if n.color == red {
+		return n
+	}
+	return n.with(red, n.left, n.right)
+}
+
+func (n *node[K, V]) put(key K, value V) (*node[K, V], bool) {
+	if n == nil {
+		return &node[K, V]{key: key, value: value, color: red}, true
+	}
+	c := key.Compare(n.key)
+	switch {
+	case c < 0:
+		l, added := n.left.put(key, value)
+		return balance(n.color, l, n, n.right), added
+	case c > 0:
+		r, added := n.right.put(key, value)
+		return balance(n.color, n.left, n, r), added
+	}
+	return &node[K, V]{key: key, value: value, color: n.color, left: n.left, right: n.right}, false
+}
+
+// balance builds a node with the key and value of `m`, rotating away red-red violations (Okasaki).
+func balance[K constraints.Comparable[K], V any](c color, l, m, r *node[K, V]) *node[K, V] {
+	if c == black {
+		switch {
+		case l.isRed() && l.left.isRed():
+			return l.with(red, l.left.with(black, l.left.left, l.left.right), m.with(black, l.right, r))
+		case l.isRed() && l.right.isRed():
+			return l.right.with(red, l.with(black, l.left, l.right.left), m.with(black, l.right.right, r))
+		case r.isRed() && r.left.isRed():
+			return r.left.with(red, m.with(black, l, r.left.left), r.with(black, r.left.right, r.right))
+		case r.isRed() && r.right.isRed():
+			return r.with(red, m.with(black, l, r.left), r.right.with(black, r.right.left, r.right.right))
+		}
+	}
+	return m.with(c, l, r)
+}
+
+// del removes the key from the subtree, which must contain it (Kahrs).
+func (n *node[K, V]) del(key K) *node[K, V] {
+	c := key.Compare(n.key)
+	switch {
+	case c < 0:
+		l := n.left.del(key)
+		if n.left.isBlack() {
+			return balLeft(l, n, n.right)
+		}
+		return n.with(red, l, n.right)
+	case c > 0:
+		r := n.right.del(key)
+		if n.right.isBlack() {
+			return balRight(n.left, n, r)
+		}
+		return n.with(red, n.left, r)
+	}
+	return fuse(n.left, n.right)
+}
+
+func balLeft[K constraints.Comparable[K], V any](l, m, r *node[K, V]) *node[K, V] {
+	switch {
+	case l.isRed():
+		return m.with(red, l.with(black, l.left, l.right), r)
+	case r.isBlack():
+		return balance(black, l, m, r.redden())
+	case r.isRed() && r.left.isBlack():
+		rl := r.left
+		return rl.with(red, m.with(black, l, rl.left), balance(black, rl.right, r, r.right.redden()))
+	}
+	panic("bad persistent red-black node")
+}
+
+func balRight[K constraints.Comparable[K], V any](l, m, r *node[K, V]) *node[K, V] {
+	switch {
+	case r.isRed():
+		return m.with(red, l, r.with(black, r.left, r.right))
+	case l.isBlack():
+		return balance(black, l.redden(), m, r)
+	case l.isRed() && l.right.isBlack():
+		lr := l.right
+		return lr.with(red, balance(black, l.left.redden(), l, lr.left), m.with(black, lr.right, r))
+	}
+	panic("bad persistent red-black node")
+}
+
+func fuse[K constraints.Comparable[K], V any](l, r *node[K, V]) *node[K, V] {
+	switch {
+	case l == nil:
+		return r
+	case r == nil:
+		return l
+	case l.isRed() && r.isRed():
+		s := fuse(l.right, r.left)
+		if s.isRed() {
+			return s.with(red, l.with(red, l.left, s.left), r.with(red, s.right, r.right))
+		}
+		return l.with(red, l.left, r.with(red, s, r.right))
+	case l.isBlack() && r.isBlack():
+		s := fuse(l.right, r.left)
+		if s.isRed() {
+			return s.with(red, l.with(black, l.left, s.left), r.with(black, s.right, r.right))
+		}
+		return balLeft(l.left, l, r.with(black, s, r.right))
+	case r.isRed():
+		return r.with(red, fuse(l, r.left), r.right)
+	}
+	return l.with(red, l.left, fuse(l.right, r))
+}
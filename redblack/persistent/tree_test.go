@@ -0,0 +1,79 @@
+package persistent
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type compInt int
+
+func (i1 compInt) Compare(i2 compInt) int { return int(i1) - int(i2) }
+
+func blackHeight[V any](n *node[compInt, V]) (int, bool) {
+	if n == nil {
+		return 1, true
+	}
+	if n.isRed() && (n.left.isRed() || n.right.isRed()) {
+		return 0, false
+	}
+	lh, ok := blackHeight(n.left)
+	if !ok {
+		return 0, false
+	}
+	rh, ok := blackHeight(n.right)
+	if !ok || lh != rh {
+		return 0, false
+	}
+	if n.isBlack() {
+		lh++
+	}
+	return lh, true
+}
+
+func TestPersistence(t *testing.T) {
+	a := assert.New(t)
+
+	type version struct {
+		tree *Tree[compInt, int]
+		m    map[compInt]int
+	}
+	var versions []version
+	tr := NewTree[compInt, int]()
+	m := make(map[compInt]int)
+	for i := 0; i < 3_000; i++ {
+		k := compInt(rand.Intn(300))
+		if rand.Intn(3) == 0 {
+			tr = tr.Delete(k)
+			delete(m, k)
+		} else {
+			tr = tr.Put(k, i)
+			m[k] = i
+		}
+		_, ok := blackHeight(tr.root)
+		a.True(ok)
+		a.False(tr.root.isRed())
+		if i%100 == 0 {
+			snapshot := make(map[compInt]int, len(m))
+			for k, v := range m {
+				snapshot[k] = v
+			}
+			versions = append(versions, version{tree: tr, m: snapshot})
+		}
+	}
+
+	for _, v := range versions {
+		a.Equal(len(v.m), v.tree.Size())
+		keys := make([]compInt, 0, len(v.m))
+		for k, val := range v.m {
+			keys = append(keys, k)
+			got, ok := v.tree.Get(k)
+			a.True(ok)
+			a.Equal(val, got)
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+		a.Equal(keys, v.tree.Keys())
+	}
+}
@@ -0,0 +1,92 @@
+package redblack
+
+import "github.com/fealsamh/datastructures/constraints"
+
+// NodePool allocates red-black nodes from slabs and recycles deleted nodes.
+// It can be shared by many trees of the same type to relieve the garbage collector.
+// It isn't safe for concurrent use.
+type NodePool[K any, V any] struct {
+	slabSize int
+	slabs    [][]node[K, V]
+	// index of the current slab and of the next unused node in it
+	slab, pos int
+	// free nodes linked through their right child pointers
+	free *node[K, V]
+}
+
+// NewNodePool creates a new node pool allocating slabs of the given number of nodes.
+func NewNodePool[K any, V any](slabSize int) *NodePool[K, V] {
+	if slabSize < 1 {
+		panic("slab size must be positive")
+	}
+	return &NodePool[K, V]{slabSize: slabSize}
+}
+
+// NewTreeWithPool creates a new red-black tree allocating its nodes from a pool.
+func NewTreeWithPool[K constraints.Comparable[K], V any](pool *NodePool[K, V]) *Tree[K, V] {
+	return NewTreeFuncWithPool(compareMethod[K], pool)
+}
+
+// NewTreeFuncWithPool creates a new red-black tree whose keys are ordered by a comparison function
+// allocating its nodes from a pool.
+func NewTreeFuncWithPool[K any, V any](compare func(K, K) int, pool *NodePool[K, V]) *Tree[K, V] {
+	return &Tree[K, V]{compare: compare, pool: pool}
+}
+
+// Reset makes all the nodes of the pool available again.
+// All the trees using the pool must be discarded beforehand.
+func (p *NodePool[K, V]) Reset() {
+	for i := 0; i <= p.slab && i < len(p.slabs); i++ {
+		clear(p.slabs[i])
+	}
+	p.slab, p.pos, p.free = 0, 0, nil
+}
+
+func (p *NodePool[K, V]) get() *node[K, V] {
+	if n := p.free; n != nil {
+		p.free = n.right
+		n.right = nil
+		return n
+	}
+	if p.slab < len(p.slabs) && p.pos == p.slabSize {
+		p.slab, p.pos = p.slab+1, 0
+	}
+	if p.slab == len(p.slabs) {
+		p.slabs = append(p.slabs, make([]node[K, V], p.slabSize))
+	}
+	n := &p.slabs[p.slab][p.pos]
+	p.pos++
+	return n
+}
+
+func (p *NodePool[K, V]) put(n *node[K, V]) {
+	*n = node[K, V]{right: p.free}
+	p.free = n
+}
+
+func (t *Tree[K, V]) newNode(key K, value V, c color, parent *node[K, V]) *node[K, V] {
+	if t.pool == nil {
+		return &node[K, V]{key: key, value: value, color: c, parent: parent}
+	}
+	n := t.pool.get()
+	n.key, n.value, n.color, n.parent = key, value, c, parent
+	return n
+}
+
+// freeNode returns a node removed from the tree to the pool, if any.
+func (t *Tree[K, V]) freeNode(n *node[K, V]) {
+	if t.pool != nil {
+		t.pool.put(n)
+	}
+}
+
+// freeSubtree returns all the nodes of a subtree to the pool of `t`, if any.
+func (t *Tree[K, V]) freeSubtree(n *node[K, V]) {
+	if t.pool == nil || n == nil {
+		return
+	}
+	l, r := n.left, n.right
+	t.pool.put(n)
+	t.freeSubtree(l)
+	t.freeSubtree(r)
+}
@@ -0,0 +1,98 @@
+package redblack
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodePool(t *testing.T) {
+	a := assert.New(t)
+
+	pool := NewNodePool[int, int](16)
+	t1 := NewTreeFuncWithPool(func(i1, i2 int) int { return i1 - i2 }, pool)
+	t2 := NewTreeFuncWithPool(func(i1, i2 int) int { return i1 - i2 }, pool)
+	m1, m2 := make(map[int]int), make(map[int]int)
+	for i := 0; i < 5_000; i++ {
+		tr, m := t1, m1
+		if i%2 == 1 {
+			tr, m = t2, m2
+		}
+		k := rand.Intn(200)
+		if rand.Intn(3) == 0 {
+			tr.Delete(k)
+			delete(m, k)
+		} else {
+			tr.Put(k, i)
+			m[k] = i
+		}
+	}
+	a.NoError(t1.CheckInvariants())
+	a.NoError(t2.CheckInvariants())
+	for tr, m := range map[*Tree[int, int]]map[int]int{t1: m1, t2: m2} {
+		a.Equal(len(m), tr.Size())
+		for k, v := range m {
+			got, ok := tr.Get(k)
+			a.True(ok)
+			a.Equal(v, got)
+		}
+	}
+	// at most 400 live nodes, the rest is recycled
+	a.LessOrEqual(len(pool.slabs), 400/16+1)
+
+	pool.Reset()
+	t3 := NewTreeWithPool[compString](NewNodePool[compString, int](4))
+	t3.Put("a", 1)
+	a.Equal(1, t3.Size())
+	a.Panics(func() { NewNodePool[int, int](0) })
+}
+
+func BenchmarkRedblackTreeWithPool(b *testing.B) {
+	pairs := generateTestData()
+	pool := NewNodePool[compString, int](1_024)
+	var lR interface{}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		pool.Reset()
+		t := NewTreeWithPool(pool)
+		for _, p := range pairs {
+			t.Put(compString(p.fst), p.snd)
+		}
+		keys := make([]string, 0, t.Size())
+		t.Enumerate(func(k compString, _ int) bool {
+			keys = append(keys, string(k))
+			return true
+		})
+		lR = newPair(t, keys)
+	}
+	gR = lR
+}
+
+func BenchmarkSmallTrees(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		trees := make([]*Tree[compString, int], 1_000)
+		for i := range trees {
+			trees[i] = NewTree[compString, int]()
+			for _, k := range []compString{"a", "b", "c", "d"} {
+				trees[i].Put(k, i)
+			}
+		}
+		gR = trees
+	}
+}
+
+func BenchmarkSmallTreesWithPool(b *testing.B) {
+	pool := NewNodePool[compString, int](4_096)
+	for n := 0; n < b.N; n++ {
+		pool.Reset()
+		trees := make([]*Tree[compString, int], 1_000)
+		for i := range trees {
+			trees[i] = NewTreeWithPool(pool)
+			for _, k := range []compString{"a", "b", "c", "d"} {
+				trees[i].Put(k, i)
+			}
+		}
+		gR = trees
+	}
+}
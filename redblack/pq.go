@@ -0,0 +1,132 @@
+package redblack
+
+import (
+	"cmp"
+
+	"github.com/fealsamh/datastructures/constraints"
+)
+
+type pqKey[P any] struct {
+	priority P
+	seq      uint64
+}
+
+// PQ is a double-ended priority queue allowing duplicate priorities.
+// Items with equal priorities are ordered by insertion.
+type PQ[P any, T any] struct {
+	tree *Tree[pqKey[P], *Handle[P, T]]
+	seq  uint64
+}
+
+// Handle refers to an item in a priority queue.
+type Handle[P any, T any] struct {
+	Value T
+	key   pqKey[P]
+	queue *PQ[P, T]
+}
+
+// Priority returns the priority of the item.
+func (h *Handle[P, T]) Priority() P {
+	return h.key.priority
+}
+
+// NewPQ creates a new priority queue.
+func NewPQ[P constraints.Comparable[P], T any]() *PQ[P, T] {
+	return NewPQFunc[P, T](compareMethod[P])
+}
+
+// NewOrderedPQ creates a new priority queue with priorities ordered by the built-in ordering.
+func NewOrderedPQ[P cmp.Ordered, T any]() *PQ[P, T] {
+	return NewPQFunc[P, T](cmp.Compare[P])
+}
+
+// NewPQFunc creates a new priority queue with priorities ordered by a comparison function.
+func NewPQFunc[P any, T any](compare func(P, P) int) *PQ[P, T] {
+	return &PQ[P, T]{
+		tree: NewTreeFunc[pqKey[P], *Handle[P, T]](func(k1, k2 pqKey[P]) int {
+			if c := compare(k1.priority, k2.priority); c != 0 {
+				return c
+			}
+			return cmp.Compare(k1.seq, k2.seq)
+		}),
+	}
+}
+
+// Len returns the number of items in the queue.
+func (q *PQ[P, T]) Len() int {
+	return q.tree.Size()
+}
+
+// Push adds an item with the given priority to the queue.
+func (q *PQ[P, T]) Push(priority P, value T) *Handle[P, T] {
+	h := &Handle[P, T]{Value: value, queue: q}
+	q.insert(h, priority)
+	return h
+}
+
+// PeekMin returns the item with the minimum priority or nil if the queue is empty.
+func (q *PQ[P, T]) PeekMin() *Handle[P, T] {
+	if q.tree.root == nil {
+		return nil
+	}
+	return q.tree.root.min().value
+}
+
+// PeekMax returns the item with the maximum priority or nil if the queue is empty.
+func (q *PQ[P, T]) PeekMax() *Handle[P, T] {
+	if q.tree.root == nil {
+		return nil
+	}
+	return q.tree.root.max().value
+}
+
+// PopMin removes and returns the item with the minimum priority or nil if the queue is empty.
+func (q *PQ[P, T]) PopMin() *Handle[P, T] {
+	if q.tree.root == nil {
+		return nil
+	}
+	return q.pop(q.tree.root.min())
+}
+
+// PopMax removes and returns the item with the maximum priority or nil if the queue is empty.
+func (q *PQ[P, T]) PopMax() *Handle[P, T] {
+	if q.tree.root == nil {
+		return nil
+	}
+	return q.pop(q.tree.root.max())
+}
+
+// Update changes the priority of an item in the queue.
+// The item is ordered after the existing items with the same priority.
+// It panics if the item isn't in the queue.
+func (q *PQ[P, T]) Update(h *Handle[P, T], priority P) {
+	if !q.Remove(h) {
+		panic("item not found in priority queue")
+	}
+	h.queue = q
+	q.insert(h, priority)
+}
+
+// Remove removes an item from the queue.
+func (q *PQ[P, T]) Remove(h *Handle[P, T]) bool {
+	if h.queue != q {
+		return false
+	}
+	h.queue = nil
+	_, ok := q.tree.Delete(h.key)
+	return ok
+}
+
+func (q *PQ[P, T]) insert(h *Handle[P, T], priority P) {
+	q.seq++
+	h.key = pqKey[P]{priority: priority, seq: q.seq}
+	q.tree.Put(h.key, h)
+}
+
+func (q *PQ[P, T]) pop(n *node[pqKey[P], *Handle[P, T]]) *Handle[P, T] {
+	h := n.value
+	h.queue = nil
+	n.remove(q.tree)
+	q.tree.freeNode(n)
+	return h
+}
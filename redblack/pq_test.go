@@ -0,0 +1,61 @@
+package redblack
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPQ(t *testing.T) {
+	a := assert.New(t)
+
+	q := NewOrderedPQ[int, string]()
+	a.Nil(q.PeekMin())
+	a.Nil(q.PopMax())
+
+	q.Push(3, "a")
+	q.Push(1, "b")
+	h := q.Push(3, "c")
+	q.Push(2, "d")
+	q.Push(3, "e")
+	a.Equal(5, q.Len())
+	a.Equal("b", q.PeekMin().Value)
+	a.Equal("e", q.PeekMax().Value)
+
+	q.Update(h, 0)
+	a.Equal(0, h.Priority())
+	a.Equal("c", q.PopMin().Value)
+	a.False(q.Remove(h))
+	a.Panics(func() { q.Update(h, 1) })
+
+	var vs []string
+	for q.Len() > 0 {
+		vs = append(vs, q.PopMin().Value)
+	}
+	a.Equal([]string{"b", "d", "a", "e"}, vs)
+}
+
+func TestPQRandom(t *testing.T) {
+	a := assert.New(t)
+
+	q := NewOrderedPQ[int, int]()
+	var ps []int
+	for i := 0; i < 1_000; i++ {
+		p := rand.Intn(100)
+		q.Push(p, i)
+		ps = append(ps, p)
+	}
+	sort.Ints(ps)
+	for len(ps) > 0 {
+		if rand.Intn(2) == 0 {
+			a.Equal(ps[0], q.PopMin().Priority())
+			ps = ps[1:]
+		} else {
+			a.Equal(ps[len(ps)-1], q.PopMax().Priority())
+			ps = ps[:len(ps)-1]
+		}
+	}
+	a.Equal(0, q.Len())
+}
@@ -1,13 +1,40 @@
 package redblack
 
-import "github.com/fealsamh/datastructures/constraints"
+import (
+	"cmp"
+	"iter"
+	"math/bits"
+
+	"github.com/fealsamh/datastructures/constraints"
+)
 
 // Set is a generic red-black set.
-type Set[K constraints.Comparable[K]] Tree[K, struct{}]
+type Set[K any] Tree[K, struct{}]
 
 // NewSet creates a new red-black set.
 func NewSet[K constraints.Comparable[K]]() *Set[K] { return (*Set[K])(NewTree[K, struct{}]()) }
 
+// NewSetFunc creates a new red-black set whose elements are ordered by a comparison function.
+func NewSetFunc[K any](compare func(K, K) int) *Set[K] {
+	return (*Set[K])(NewTreeFunc[K, struct{}](compare))
+}
+
+// NewOrderedSet creates a new red-black set whose elements are ordered by the built-in ordering.
+func NewOrderedSet[K cmp.Ordered]() *Set[K] { return (*Set[K])(NewOrderedTree[K, struct{}]()) }
+
+// NewSetFromSorted creates a new red-black set from strictly ascending elements in linear time.
+func NewSetFromSorted[K constraints.Comparable[K]](keys []K) (*Set[K], error) {
+	if err := checkSorted(compareMethod[K], keys); err != nil {
+		return nil, err
+	}
+	return (*Set[K])(newTreeFromSorted[K, struct{}](compareMethod[K], keys, nil)), nil
+}
+
+// Clone returns a copy of the set in linear time.
+func (s *Set[K]) Clone() *Set[K] {
+	return (*Set[K])((*Tree[K, struct{}])(s).Clone())
+}
+
 // Depth returns the depth of the set.
 func (s *Set[K]) Depth() int {
 	return (*Tree[K, struct{}])(s).Depth()
@@ -23,11 +50,71 @@ func (s *Set[K]) Values() []K {
 	return (*Tree[K, struct{}])(s).Keys()
 }
 
+// Enumerate enumerates all the elements of the set.
+func (s *Set[K]) Enumerate(f func(K) bool) bool {
+	return (*Tree[K, struct{}])(s).Enumerate(func(k K, _ struct{}) bool {
+		return f(k)
+	})
+}
+
+// All returns an iterator over the elements of the set in ascending order.
+func (s *Set[K]) All() iter.Seq[K] {
+	return (*Tree[K, struct{}])(s).KeysSeq()
+}
+
 // MinKey returns the minimum element of the set or nil if the set is empty.
 func (s *Set[K]) MinKey() *K {
 	return (*Tree[K, struct{}])(s).MinKey()
 }
 
+// MaxKey returns the maximum element of the set or nil if the set is empty.
+func (s *Set[K]) MaxKey() *K {
+	return (*Tree[K, struct{}])(s).MaxKey()
+}
+
+// Range enumerates the elements between `lo` and `hi` in ascending order.
+// The flags determine whether the bounds themselves are included.
+func (s *Set[K]) Range(lo, hi K, loInclusive, hiInclusive bool, f func(K) bool) bool {
+	return (*Tree[K, struct{}])(s).Range(lo, hi, loInclusive, hiInclusive, func(k K, _ struct{}) bool {
+		return f(k)
+	})
+}
+
+// Floor returns the greatest element less than or equal to the given one.
+func (s *Set[K]) Floor(key K) (K, bool) {
+	k, _, ok := (*Tree[K, struct{}])(s).Floor(key)
+	return k, ok
+}
+
+// Ceiling returns the least element greater than or equal to the given one.
+func (s *Set[K]) Ceiling(key K) (K, bool) {
+	k, _, ok := (*Tree[K, struct{}])(s).Ceiling(key)
+	return k, ok
+}
+
+// Lower returns the greatest element strictly less than the given one.
+func (s *Set[K]) Lower(key K) (K, bool) {
+	k, _, ok := (*Tree[K, struct{}])(s).Lower(key)
+	return k, ok
+}
+
+// Higher returns the least element strictly greater than the given one.
+func (s *Set[K]) Higher(key K) (K, bool) {
+	k, _, ok := (*Tree[K, struct{}])(s).Higher(key)
+	return k, ok
+}
+
+// Rank returns the number of elements in the set that are less than the given one.
+func (s *Set[K]) Rank(key K) int {
+	return (*Tree[K, struct{}])(s).Rank(key)
+}
+
+// Select returns the i-th smallest element of the set (counting from zero).
+func (s *Set[K]) Select(i int) (K, bool) {
+	k, _, ok := (*Tree[K, struct{}])(s).Select(i)
+	return k, ok
+}
+
 // Insert inserts a new element into the set.
 func (s *Set[K]) Insert(key K) bool {
 	_, ok := (*Tree[K, struct{}])(s).Put(key, struct{}{})
@@ -39,3 +126,137 @@ func (s *Set[K]) Contains(key K) bool {
 	_, ok := (*Tree[K, struct{}])(s).Get(key)
 	return ok
 }
+
+// Remove removes an element from the set.
+func (s *Set[K]) Remove(key K) bool {
+	_, ok := (*Tree[K, struct{}])(s).Delete(key)
+	return ok
+}
+
+// Union returns a new set with the elements of both sets.
+func (s *Set[K]) Union(s2 *Set[K]) *Set[K] {
+	return mergeSets(s, s2, func(in1, in2 bool) bool { return true })
+}
+
+// Intersection returns a new set with the elements common to both sets.
+// It looks up the elements of the smaller set in the larger one when that's cheaper than merging them.
+func (s *Set[K]) Intersection(s2 *Set[K]) *Set[K] {
+	small, large := s2, s
+	if s.Size() < s2.Size() {
+		small, large = s, s2
+	}
+	if probing(small, large) {
+		return probeSets(s, small, large, func(found bool) bool { return found })
+	}
+	return mergeSets(s, s2, func(in1, in2 bool) bool { return in1 && in2 })
+}
+
+// Difference returns a new set with the elements of `s` that aren't in `s2`.
+// It looks up the elements of `s` in `s2` when that's cheaper than merging the sets.
+func (s *Set[K]) Difference(s2 *Set[K]) *Set[K] {
+	if probing(s, s2) {
+		return probeSets(s, s, s2, func(found bool) bool { return !found })
+	}
+	return mergeSets(s, s2, func(in1, in2 bool) bool { return in1 && !in2 })
+}
+
+// SymmetricDifference returns a new set with the elements found in exactly one of the sets.
+func (s *Set[K]) SymmetricDifference(s2 *Set[K]) *Set[K] {
+	return mergeSets(s, s2, func(in1, in2 bool) bool { return in1 != in2 })
+}
+
+// UnionWith inserts the elements of `s2` into the set.
+func (s *Set[K]) UnionWith(s2 *Set[K]) {
+	for k := range s2.All() {
+		s.Insert(k)
+	}
+}
+
+// IntersectWith removes the elements that aren't in `s2` from the set.
+func (s *Set[K]) IntersectWith(s2 *Set[K]) {
+	(*Tree[K, struct{}])(s).DeleteIf(func(k K, _ struct{}) bool { return !s2.Contains(k) })
+}
+
+// DifferenceWith removes the elements of `s2` from the set.
+func (s *Set[K]) DifferenceWith(s2 *Set[K]) {
+	for k := range s2.All() {
+		s.Remove(k)
+	}
+}
+
+// SymmetricDifferenceWith removes the elements of `s2` found in the set and inserts the others.
+func (s *Set[K]) SymmetricDifferenceWith(s2 *Set[K]) {
+	for k := range s2.All() {
+		if !s.Remove(k) {
+			s.Insert(k)
+		}
+	}
+}
+
+// IsSubsetOf returns true if all the elements of the set are in `s2`.
+func (s *Set[K]) IsSubsetOf(s2 *Set[K]) bool {
+	if s.Size() > s2.Size() {
+		return false
+	}
+	for k := range s.All() {
+		if !s2.Contains(k) {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal returns true if both sets have the same elements.
+func (s *Set[K]) Equal(s2 *Set[K]) bool {
+	return s.Size() == s2.Size() && s.IsSubsetOf(s2)
+}
+
+// mergeSets walks both sets in order and builds a new set from the elements selected by `keep`.
+func mergeSets[K any](s1, s2 *Set[K], keep func(in1, in2 bool) bool) *Set[K] {
+	var ks []K
+	walk((*Tree[K, struct{}])(s1), (*Tree[K, struct{}])(s2), func(n1, n2 *node[K, struct{}]) {
+		if keep(n1 != nil, n2 != nil) {
+			if n1 != nil {
+				ks = append(ks, n1.key)
+			} else {
+				ks = append(ks, n2.key)
+			}
+		}
+	})
+	s := (*Tree[K, struct{}])(s1).newEmpty()
+	s.build(ks, nil)
+	return (*Set[K])(s)
+}
+
+// probing returns true if looking up the elements of `small` in `large` is cheaper than merging the sets.
+func probing[K any](small, large *Set[K]) bool {
+	return small.Size()*bits.Len(uint(large.Size())) < small.Size()+large.Size()
+}
+
+// probeSets looks up the elements of `small` in `large` in ascending order
+// and builds a new set like `s` from those for which `keep` holds, taking the keys from `s`.
+func probeSets[K any](s, small, large *Set[K], keep func(found bool) bool) *Set[K] {
+	t, l := (*Tree[K, struct{}])(s), (*Tree[K, struct{}])(large)
+	var compare func(K, K) int
+	if l.root != nil {
+		compare = t.comparator()
+	}
+	var ks []K
+	for k := range small.All() {
+		found := false
+		if l.root != nil {
+			if n, dir := l.root.find(compare, k); dir == exact {
+				found = true
+				if large == s {
+					k = n.key
+				}
+			}
+		}
+		if keep(found) {
+			ks = append(ks, k)
+		}
+	}
+	r := t.newEmpty()
+	r.build(ks, nil)
+	return (*Set[K])(r)
+}
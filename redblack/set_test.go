@@ -0,0 +1,97 @@
+package redblack
+
+import (
+	"testing"
+
+	"github.com/fealsamh/datastructures/constraints"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSet(ks ...string) *Set[compString] {
+	s := NewSet[compString]()
+	for _, k := range ks {
+		s.Insert(compString(k))
+	}
+	return s
+}
+
+func TestSetAlgebra(t *testing.T) {
+	a := assert.New(t)
+
+	s1 := newTestSet("a", "b", "c", "d")
+	s2 := newTestSet("c", "d", "e")
+
+	a.Equal([]compString{"a", "b", "c", "d", "e"}, s1.Union(s2).Values())
+	a.Equal([]compString{"c", "d"}, s1.Intersection(s2).Values())
+	a.Equal([]compString{"a", "b"}, s1.Difference(s2).Values())
+	a.Equal([]compString{"a", "b", "e"}, s1.SymmetricDifference(s2).Values())
+	a.Nil(s1.Intersection(NewSet[compString]()).Values())
+
+	u := s1.Union(s2)
+	a.NoError((*Tree[compString, struct{}])(u).CheckInvariants())
+	u.Insert("f")
+	a.True(u.Remove("a"))
+	a.Equal(5, u.Size())
+
+	a.True(newTestSet("c", "d").IsSubsetOf(s1))
+	a.False(s2.IsSubsetOf(s1))
+	a.True(s1.Equal(newTestSet("d", "c", "b", "a")))
+	a.False(s1.Equal(s2))
+
+	for _, tc := range []struct {
+		op   func(*Set[compString], *Set[compString])
+		want []compString
+	}{
+		{(*Set[compString]).UnionWith, []compString{"a", "b", "c", "d", "e"}},
+		{(*Set[compString]).IntersectWith, []compString{"c", "d"}},
+		{(*Set[compString]).DifferenceWith, []compString{"a", "b"}},
+		{(*Set[compString]).SymmetricDifferenceWith, []compString{"a", "b", "e"}},
+	} {
+		s := newTestSet("a", "b", "c", "d")
+		tc.op(s, s2)
+		a.Equal(tc.want, s.Values())
+	}
+}
+
+func TestOrderedInterfaces(t *testing.T) {
+	a := assert.New(t)
+
+	var m constraints.OrderedMap[compString, int] = NewTree[compString, int]()
+	m.Put("b", 2)
+	m.Put("a", 1)
+	a.Equal([]compString{"a", "b"}, m.Keys())
+
+	var s constraints.OrderedSet[compString] = newTestSet("c", "a", "b")
+	var ks []compString
+	a.False(s.Enumerate(func(k compString) bool {
+		ks = append(ks, k)
+		return k < "b"
+	}))
+	a.Equal([]compString{"a", "b"}, ks)
+}
+
+func TestSetAlgebraUnbalanced(t *testing.T) {
+	a := assert.New(t)
+
+	large := NewOrderedSet[int]()
+	for i := 0; i < 10_000; i += 2 {
+		large.Insert(i)
+	}
+	small := NewOrderedSet[int]()
+	for _, k := range []int{-1, 4, 5, 100, 9_998, 10_001} {
+		small.Insert(k)
+	}
+	a.True(probing(small, large))
+
+	a.Equal([]int{4, 100, 9_998}, small.Intersection(large).Values())
+	a.Equal([]int{4, 100, 9_998}, large.Intersection(small).Values())
+	a.Equal([]int{-1, 5, 10_001}, small.Difference(large).Values())
+	a.Equal(4_997, large.Difference(small).Size())
+	a.Nil(small.Intersection(NewOrderedSet[int]()).Values())
+	a.Equal(small.Values(), small.Difference(NewOrderedSet[int]()).Values())
+
+	s := large.Clone()
+	s.IntersectWith(small)
+	a.Equal([]int{4, 100, 9_998}, s.Values())
+	a.NoError((*Tree[int, struct{}])(s).CheckInvariants())
+}
@@ -0,0 +1,121 @@
+package redblack
+
+import "fmt"
+
+// Split moves the items with keys less than and greater than the pivot into two trees in logarithmic time.
+// The item with the pivot key, if any, is returned separately.
+// The receiver becomes the lower tree.
+func (t *Tree[K, V]) Split(key K) (lower, higher *Tree[K, V], value V, found bool) {
+	l, _, r, _, m := t.split(t.root, t.root.blackHeight(), key)
+	if m != nil {
+		value, found = m.value, true
+		t.freeNode(m)
+	}
+	lower, higher = t, t.newEmpty()
+	lower.root, higher.root = l, r
+	return
+}
+
+// Join concatenates two trees with a key-value pair in between in logarithmic time.
+// All the keys in `left` must be less than `key` and all the keys in `right` must be greater than `key`.
+// The trees must share their comparison and augmentation functions as well as their node pool, if any.
+// Both trees are consumed by the operation and mustn't be used afterwards.
+func Join[K any, V any](left *Tree[K, V], key K, value V, right *Tree[K, V]) *Tree[K, V] {
+	compare := left.resolveComparator()
+	if k := left.MaxKey(); k != nil && compare(*k, key) >= 0 {
+		panic(fmt.Sprintf("key '%v' not greater than the keys in the left tree", key))
+	}
+	if k := right.MinKey(); k != nil && compare(*k, key) <= 0 {
+		panic(fmt.Sprintf("key '%v' not less than the keys in the right tree", key))
+	}
+	t := left
+	m := t.newNode(key, value, black, nil)
+	l, r := left.root, right.root
+	left.root, right.root = nil, nil
+	t.root, _ = t.join(l, l.blackHeight(), m, r, r.blackHeight())
+	return t
+}
+
+// split splits a subtree with a black root of black height `h` owned by `t`
+// into the subtrees with keys less than and greater than the pivot and returns their black heights.
+func (t *Tree[K, V]) split(n *node[K, V], h int, key K) (l *node[K, V], hl int, r *node[K, V], hr int, m *node[K, V]) {
+	if n == nil {
+		return
+	}
+	// a red child turned black keeps the black height of its parent
+	ha, hb := h-1, h-1
+	if colorOf(n.left) == red {
+		ha++
+	}
+	if colorOf(n.right) == red {
+		hb++
+	}
+	a, b := n.left.detach(), n.right.detach()
+	c := t.comparator()(key, n.key)
+	switch {
+	case c == 0:
+		n.left, n.right, n.count = nil, nil, 1
+		return a, ha, b, hb, n
+	case c < 0:
+		al, hal, ar, har, m := t.split(a, ha, key)
+		r, hr := t.join(ar, har, n, b, hb)
+		return al, hal, r, hr, m
+	default:
+		bl, hbl, br, hbr, m := t.split(b, hb, key)
+		l, hl := t.join(a, ha, n, bl, hbl)
+		return l, hl, br, hbr, m
+	}
+}
+
+// join joins two subtrees with black roots of black heights `hl` and `hr` owned by `t`
+// using `m` as the node in between and returns the root of the result and its black height.
+// It runs in O(|hl-hr|+1) time.
+func (t *Tree[K, V]) join(l *node[K, V], hl int, m *node[K, V], r *node[K, V], hr int) (*node[K, V], int) {
+	m.parent = nil
+	switch {
+	case hl == hr:
+		m.left, m.right, m.color = l, r, black
+		if l != nil {
+			l.parent = m
+		}
+		if r != nil {
+			r.parent = m
+		}
+		m.update(t)
+		return m, hl + 1
+	case hl > hr:
+		p, c, h := (*node[K, V])(nil), l, hl
+		for colorOf(c) != black || h != hr {
+			if c.color == black {
+				h--
+			}
+			p, c = c, c.right
+		}
+		m.left, m.right, m.color, m.parent = c, r, red, p
+		p.right = m
+		t.root = l
+	default:
+		p, c, h := (*node[K, V])(nil), r, hr
+		for colorOf(c) != black || h != hl {
+			if c.color == black {
+				h--
+			}
+			p, c = c, c.left
+		}
+		m.left, m.right, m.color, m.parent = l, c, red, p
+		p.left = m
+		t.root = r
+	}
+	if m.left != nil {
+		m.left.parent = m
+	}
+	if m.right != nil {
+		m.right.parent = m
+	}
+	m.updatePath(t)
+	h := max(hl, hr)
+	if m.ensureInvariants(t) {
+		h++
+	}
+	return t.root, h
+}
@@ -0,0 +1,122 @@
+package redblack
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func checkTree(a *assert.Assertions, t *Tree[compString, int]) {
+	a.NoError(t.CheckInvariants())
+	a.Equal(len(t.Keys()), t.Size())
+}
+
+func TestSplitJoin(t *testing.T) {
+	a := assert.New(t)
+
+	for i := 0; i < 50; i++ {
+		tr := NewTree[compString, int]()
+		size := rand.Intn(500)
+		for j := 0; j < size; j++ {
+			n := rand.Intn(1_000)
+			tr.Put(compString(fmt.Sprintf("k%03d", n)), n)
+		}
+		keys := tr.Keys()
+		pivot := rand.Intn(1_000)
+		pk := compString(fmt.Sprintf("k%03d", pivot))
+		_, exists := tr.Get(pk)
+
+		lower, higher, v, found := tr.Split(pk)
+		a.Equal(exists, found)
+		if found {
+			a.Equal(pivot, v)
+		}
+		checkTree(a, lower)
+		checkTree(a, higher)
+		for _, k := range lower.Keys() {
+			a.Less(string(k), string(pk))
+		}
+		for _, k := range higher.Keys() {
+			a.Greater(string(k), string(pk))
+		}
+
+		joined := Join(lower, pk, pivot, higher)
+		checkTree(a, joined)
+		if !exists {
+			a.Equal(len(keys)+1, joined.Size())
+			joined.Delete(pk)
+		}
+		a.Equal(keys, joined.Keys())
+	}
+}
+
+func TestJoinPanics(t *testing.T) {
+	a := assert.New(t)
+
+	l, r := NewTree[compString, int](), NewTree[compString, int]()
+	l.Put("b", 1)
+	r.Put("d", 2)
+	a.Panics(func() { Join(l, "a", 0, r) })
+	a.Panics(func() { Join(l, "e", 0, r) })
+}
+
+func TestSplitThenModify(t *testing.T) {
+	a := assert.New(t)
+
+	tr := NewOrderedTree[int, int]()
+	for i := 0; i < 1_000; i++ {
+		tr.Put(i, i)
+	}
+	lower, higher, _, _ := tr.Split(500)
+	for i := 0; i < 500; i += 3 {
+		lower.Delete(i)
+		higher.Delete(500 + i)
+		higher.Put(2_000+i, i)
+	}
+	a.NoError(lower.CheckInvariants())
+	a.NoError(higher.CheckInvariants())
+	a.Equal(333, lower.Size())
+	a.Equal(500, higher.Size())
+
+	joined := Join(lower, 500, 500, higher)
+	a.NoError(joined.CheckInvariants())
+	joined.Put(-1, -1)
+	a.Equal(-1, *joined.MinKey())
+	a.NoError(joined.CheckInvariants())
+}
+
+func BenchmarkSplitJoin(b *testing.B) {
+	for _, size := range []int{1 << 10, 1 << 20} {
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+			tr := NewOrderedTree[int, int]()
+			for i := 0; i < size; i++ {
+				tr.Put(2*i, i)
+			}
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				pivot := 2*rand.Intn(size) + 1
+				l, h, _, _ := tr.Split(pivot)
+				tr = Join(l, pivot, 0, h)
+				tr.Delete(pivot)
+			}
+		})
+	}
+}
+
+func TestSplitHeights(t *testing.T) {
+	a := assert.New(t)
+
+	tr := NewOrderedTree[int, int]()
+	for i := 0; i < 2_000; i++ {
+		n := rand.Intn(4_000)
+		tr.Put(n, n)
+	}
+	for i := 0; i < 100; i++ {
+		c := tr.Clone()
+		l, hl, r, hr, _ := c.split(c.root, c.root.blackHeight(), rand.Intn(4_000))
+		a.Equal(l.blackHeight(), hl)
+		a.Equal(r.blackHeight(), hr)
+	}
+}
@@ -0,0 +1,165 @@
+package redblack
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// SyncTree is a red-black tree safe for concurrent use.
+// Reads proceed concurrently while writes are exclusive.
+type SyncTree[K any, V any] struct {
+	mu       sync.RWMutex
+	tree     *Tree[K, V]
+	snapshot atomic.Pointer[View[K, V]]
+	// serializes the copying of snapshots
+	snapshotMu sync.Mutex
+}
+
+// NewSyncTree wraps a red-black tree for concurrent use.
+// The tree mustn't be accessed directly afterwards.
+func NewSyncTree[K any, V any](t *Tree[K, V]) *SyncTree[K, V] {
+	return &SyncTree[K, V]{tree: t}
+}
+
+// Size returns the size of the tree.
+func (t *SyncTree[K, V]) Size() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.tree.Size()
+}
+
+// Get returns the value for the given key.
+func (t *SyncTree[K, V]) Get(key K) (V, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.tree.Get(key)
+}
+
+// Keys returns the keys of the items in the tree.
+func (t *SyncTree[K, V]) Keys() []K {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.tree.Keys()
+}
+
+// Enumerate enumerates all the items in the tree.
+// The tree is read-locked during the enumeration so `f` mustn't modify it.
+func (t *SyncTree[K, V]) Enumerate(f func(K, V) bool) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.tree.Enumerate(f)
+}
+
+// Range enumerates the items whose keys lie between `lo` and `hi` in ascending order.
+// The tree is read-locked during the enumeration so `f` mustn't modify it.
+func (t *SyncTree[K, V]) Range(lo, hi K, loInclusive, hiInclusive bool, f func(K, V) bool) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.tree.Range(lo, hi, loInclusive, hiInclusive, f)
+}
+
+// MinKey returns the minimum key in the tree and false if the tree is empty.
+// The key is copied since the node holding it may be reused once the lock is released.
+func (t *SyncTree[K, V]) MinKey() (K, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return deref(t.tree.MinKey())
+}
+
+// MaxKey returns the maximum key in the tree and false if the tree is empty.
+// The key is copied since the node holding it may be reused once the lock is released.
+func (t *SyncTree[K, V]) MaxKey() (K, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return deref(t.tree.MaxKey())
+}
+
+func deref[K any](k *K) (K, bool) {
+	if k == nil {
+		var zero K
+		return zero, false
+	}
+	return *k, true
+}
+
+// Put inserts a new key-value pair into the tree or replaces the value for an existing key.
+func (t *SyncTree[K, V]) Put(key K, value V) (V, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.snapshot.Store(nil)
+	return t.tree.Put(key, value)
+}
+
+// GetElsePut returns the value for the given key or inserts a new key-value pair into the tree.
+func (t *SyncTree[K, V]) GetElsePut(key K, fnValue func() V) (V, bool) {
+	if v, ok := t.Get(key); ok {
+		return v, true
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.snapshot.Store(nil)
+	return t.tree.GetElsePut(key, fnValue)
+}
+
+// Delete removes the key from the tree and returns its former value.
+func (t *SyncTree[K, V]) Delete(key K) (V, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.snapshot.Store(nil)
+	return t.tree.Delete(key)
+}
+
+// Update runs `f` with exclusive access to the underlying tree.
+func (t *SyncTree[K, V]) Update(f func(*Tree[K, V])) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.snapshot.Store(nil)
+	f(t.tree)
+}
+
+// Snapshot returns an immutable view of the current contents of the tree.
+// The view is copied at most once between writes and shared by all the readers.
+func (t *SyncTree[K, V]) Snapshot() *View[K, V] {
+	if v := t.snapshot.Load(); v != nil {
+		return v
+	}
+	t.snapshotMu.Lock()
+	defer t.snapshotMu.Unlock()
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	// writers clear the snapshot under the write lock so it's up to date here if set
+	if v := t.snapshot.Load(); v != nil {
+		return v
+	}
+	v := &View[K, V]{tree: t.tree.Clone()}
+	t.snapshot.Store(v)
+	return v
+}
+
+// View is an immutable view of a red-black tree.
+type View[K any, V any] struct {
+	tree *Tree[K, V]
+}
+
+// Size returns the size of the view.
+func (v *View[K, V]) Size() int { return v.tree.Size() }
+
+// Get returns the value for the given key.
+func (v *View[K, V]) Get(key K) (V, bool) { return v.tree.Get(key) }
+
+// Keys returns the keys of the items in the view.
+func (v *View[K, V]) Keys() []K { return v.tree.Keys() }
+
+// Enumerate enumerates all the items in the view.
+func (v *View[K, V]) Enumerate(f func(K, V) bool) bool { return v.tree.Enumerate(f) }
+
+// Range enumerates the items whose keys lie between `lo` and `hi` in ascending order.
+func (v *View[K, V]) Range(lo, hi K, loInclusive, hiInclusive bool, f func(K, V) bool) bool {
+	return v.tree.Range(lo, hi, loInclusive, hiInclusive, f)
+}
+
+// MinKey returns the minimum key in the view or nil if the view is empty.
+func (v *View[K, V]) MinKey() *K { return v.tree.MinKey() }
+
+// MaxKey returns the maximum key in the view or nil if the view is empty.
+func (v *View[K, V]) MaxKey() *K { return v.tree.MaxKey() }
@@ -0,0 +1,141 @@
+package redblack
+
+import (
+	"cmp"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncTree(t *testing.T) {
+	a := assert.New(t)
+
+	st := NewSyncTree(NewOrderedTree[int, string]())
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				k := w*1_000 + i
+				st.Put(k, fmt.Sprint(k))
+				if i%3 == 0 {
+					st.Delete(k)
+				}
+				st.GetElsePut(-k, func() string { return "neg" })
+			}
+		}(w)
+	}
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				st.Get(i)
+				st.Range(0, 100, true, false, func(int, string) bool { return true })
+				v := st.Snapshot()
+				size := 0
+				v.Enumerate(func(k int, _ string) bool {
+					size++
+					return true
+				})
+				a.Equal(v.Size(), size)
+			}
+		}()
+	}
+	wg.Wait()
+
+	v := st.Snapshot()
+	a.Same(v, st.Snapshot())
+	a.Equal(st.Size(), v.Size())
+	st.Update(func(tr *Tree[int, string]) {
+		a.NoError(tr.CheckInvariants())
+		tr.Put(1_000_000, "x")
+	})
+	a.NotSame(v, st.Snapshot())
+	_, ok := v.Get(1_000_000)
+	a.False(ok)
+	_, ok = st.Snapshot().Get(1_000_000)
+	a.True(ok)
+}
+
+func TestSyncTreeWithPool(t *testing.T) {
+	a := assert.New(t)
+
+	pool := NewNodePool[int, int](64)
+	tr := NewTreeFuncWithPool(cmp.Compare[int], pool)
+	for i := 0; i < 2_000; i++ {
+		tr.Put(i, i)
+	}
+	st := NewSyncTree(tr)
+	var wg sync.WaitGroup
+	for r := 0; r < 8; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				a.GreaterOrEqual(st.Snapshot().Size(), 2_000)
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			st.Put(2_000+i, i)
+		}
+	}()
+	wg.Wait()
+
+	// snapshots don't take their nodes from the pool
+	v := st.Snapshot()
+	pool.Reset()
+	a.Equal(2_020, v.Size())
+	a.Equal(2_019, *v.MaxKey())
+	val, ok := v.Get(1_000)
+	a.True(ok)
+	a.Equal(1_000, val)
+	a.NoError(v.tree.CheckInvariants())
+}
+
+func TestSyncTreeMinMaxWithPool(t *testing.T) {
+	a := assert.New(t)
+
+	tr := NewTreeFuncWithPool(cmp.Compare[int], NewNodePool[int, int](64))
+	for i := 0; i < 1_000; i++ {
+		tr.Put(i, i)
+	}
+	st := NewSyncTree(tr)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1_000; i++ {
+			st.Delete(i)
+			st.Put(1_000+i, i)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1_000; i++ {
+			lo, ok1 := st.MinKey()
+			hi, ok2 := st.MaxKey()
+			a.True(ok1)
+			a.True(ok2)
+			a.LessOrEqual(lo, hi)
+		}
+	}()
+	wg.Wait()
+
+	lo, ok := st.MinKey()
+	a.True(ok)
+	a.Equal(1_000, lo)
+	hi, ok := st.MaxKey()
+	a.True(ok)
+	a.Equal(1_999, hi)
+	st.Update(func(tr *Tree[int, int]) { tr.DeleteIf(func(int, int) bool { return true }) })
+	_, ok = st.MinKey()
+	a.False(ok)
+}
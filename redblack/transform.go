@@ -0,0 +1,92 @@
+package redblack
+
+// MapValues creates a new tree with the same keys and shape as `t` and the values transformed by `f`.
+// It runs in linear time.
+func MapValues[K any, V any, W any](t *Tree[K, V], f func(K, V) W) *Tree[K, W] {
+	r := NewTreeFunc[K, W](t.compare)
+	if t.root != nil {
+		r.root = mapNode(t.root, nil, f)
+	}
+	return r
+}
+
+func mapNode[K any, V any, W any](n *node[K, V], parent *node[K, W], f func(K, V) W) *node[K, W] {
+	m := &node[K, W]{key: n.key, color: n.color, parent: parent, count: n.count}
+	if n.left != nil {
+		m.left = mapNode(n.left, m, f)
+	}
+	m.value = f(n.key, n.value)
+	if n.right != nil {
+		m.right = mapNode(n.right, m, f)
+	}
+	return m
+}
+
+// Filter creates a new tree with the items of `t` satisfying `pred`. It runs in linear time.
+func Filter[K any, V any](t *Tree[K, V], pred func(K, V) bool) *Tree[K, V] {
+	in, _ := partition(t, pred, false)
+	return in
+}
+
+// Partition creates two new trees with the items of `t` that do and don't satisfy `pred`.
+// It runs in linear time.
+func Partition[K any, V any](t *Tree[K, V], pred func(K, V) bool) (in, out *Tree[K, V]) {
+	return partition(t, pred, true)
+}
+
+func partition[K any, V any](t *Tree[K, V], pred func(K, V) bool, withOut bool) (in, out *Tree[K, V]) {
+	var (
+		inKeys, outKeys     []K
+		inValues, outValues []V
+	)
+	t.Enumerate(func(k K, v V) bool {
+		if pred(k, v) {
+			inKeys, inValues = append(inKeys, k), append(inValues, v)
+		} else if withOut {
+			outKeys, outValues = append(outKeys, k), append(outValues, v)
+		}
+		return true
+	})
+	in = t.newEmpty()
+	in.build(inKeys, inValues)
+	if withOut {
+		out = t.newEmpty()
+		out.build(outKeys, outValues)
+	}
+	return
+}
+
+// Fold combines the items of `t` in ascending key order into a single value.
+func Fold[K any, V any, A any](t *Tree[K, V], init A, f func(A, K, V) A) A {
+	acc := init
+	t.Enumerate(func(k K, v V) bool {
+		acc = f(acc, k, v)
+		return true
+	})
+	return acc
+}
+
+// DeleteIf removes the items satisfying `pred` from the tree and returns their number.
+func (t *Tree[K, V]) DeleteIf(pred func(K, V) bool) int {
+	if t.root == nil {
+		return 0
+	}
+	var doomed []*node[K, V]
+	for n := t.root.min(); n != nil; n = n.next() {
+		if pred(n.key, n.value) {
+			doomed = append(doomed, n)
+		}
+	}
+	for _, n := range doomed {
+		n.remove(t)
+		key, value := n.key, n.value
+		t.freeNode(n)
+		t.deleted(key, value)
+	}
+	return len(doomed)
+}
+
+// RetainIf removes the items not satisfying `pred` from the tree and returns their number.
+func (t *Tree[K, V]) RetainIf(pred func(K, V) bool) int {
+	return t.DeleteIf(func(k K, v V) bool { return !pred(k, v) })
+}
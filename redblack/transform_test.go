@@ -0,0 +1,44 @@
+package redblack
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransforms(t *testing.T) {
+	a := assert.New(t)
+
+	tr := NewOrderedTree[int, int]()
+	for i := 0; i < 100; i++ {
+		tr.Put(i, i*i)
+	}
+
+	m := MapValues(tr, func(k, v int) string { return strconv.Itoa(v) })
+	a.NoError(m.CheckInvariants())
+	a.Equal(tr.Keys(), m.Keys())
+	s, _ := m.Get(7)
+	a.Equal("49", s)
+
+	even := func(k, _ int) bool { return k%2 == 0 }
+	f := Filter(tr, even)
+	a.NoError(f.CheckInvariants())
+	a.Equal(50, f.Size())
+
+	in, out := Partition(tr, even)
+	a.NoError(in.CheckInvariants())
+	a.NoError(out.CheckInvariants())
+	a.Equal(f.Keys(), in.Keys())
+	a.Equal(50, out.Size())
+	k, _, _ := out.Select(0)
+	a.Equal(1, k)
+
+	a.Equal(4950, Fold(tr, 0, func(acc, k, _ int) int { return acc + k }))
+
+	a.Equal(50, tr.DeleteIf(even))
+	a.NoError(tr.CheckInvariants())
+	a.Equal(out.Keys(), tr.Keys())
+	a.Equal(40, tr.RetainIf(func(k, _ int) bool { return k < 20 }))
+	a.Equal([]int{1, 3, 5, 7, 9, 11, 13, 15, 17, 19}, tr.Keys())
+}
@@ -1,25 +1,190 @@
 package redblack
 
-import "github.com/fealsamh/datastructures/constraints"
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"math/bits"
+	"slices"
+
+	"github.com/fealsamh/datastructures/constraints"
+)
 
 // Tree is a generic red-black tree.
-type Tree[K constraints.Comparable[K], V any] struct {
-	root *node[K, V]
+// The zero value is an empty tree ordered by the Compare method of its keys,
+// which must then implement constraints.Comparable. This is notably the case of trees
+// allocated as struct fields by decoders such as encoding/json.
+type Tree[K any, V any] struct {
+	root    *node[K, V]
+	compare func(K, K) int
+	augment func(value, left, right *V)
+	hooks   *Hooks[K, V]
+	pool    *NodePool[K, V]
 }
 
 // NewTree creates a new red-black tree.
-func NewTree[K constraints.Comparable[K], V any]() *Tree[K, V] { return new(Tree[K, V]) }
+func NewTree[K constraints.Comparable[K], V any]() *Tree[K, V] {
+	return NewTreeFunc[K, V](compareMethod[K])
+}
+
+// NewTreeFunc creates a new red-black tree whose keys are ordered by a comparison function.
+func NewTreeFunc[K any, V any](compare func(K, K) int) *Tree[K, V] {
+	return &Tree[K, V]{compare: compare}
+}
+
+// NewAugmentedTreeFunc creates a new red-black tree whose values carry summaries of their subtrees.
+// Whenever a subtree changes, `augment` is called to recompute the summary in the value at its root
+// from the values at the roots of its left and right subtrees (nil if empty).
+func NewAugmentedTreeFunc[K any, V any](compare func(K, K) int, augment func(value, left, right *V)) *Tree[K, V] {
+	return &Tree[K, V]{compare: compare, augment: augment}
+}
+
+// NewOrderedTree creates a new red-black tree whose keys are ordered by the built-in ordering.
+func NewOrderedTree[K cmp.Ordered, V any]() *Tree[K, V] {
+	return NewTreeFunc[K, V](cmp.Compare[K])
+}
+
+func compareMethod[K constraints.Comparable[K]](k1, k2 K) int { return k1.Compare(k2) }
+
+// comparator returns the comparison function of the tree's keys.
+// Zero-value trees are ordered by the Compare method of their keys and panic if there's none.
+func (t *Tree[K, V]) comparator() func(K, K) int {
+	if t.compare != nil {
+		return t.compare
+	}
+	if !keysComparable[K]() {
+		var k K
+		panic(fmt.Sprintf("red-black tree of %T keys must be created with a comparison function", k))
+	}
+	return compareDynamic[K]
+}
+
+// resolveComparator fixes the comparison function of a zero-value tree on its first mutation.
+func (t *Tree[K, V]) resolveComparator() func(K, K) int {
+	if t.compare == nil {
+		t.compare = t.comparator()
+	}
+	return t.compare
+}
+
+// keysComparable returns true if the keys implement constraints.Comparable.
+func keysComparable[K any]() bool {
+	var k K
+	_, ok := any(k).(constraints.Comparable[K])
+	return ok
+}
+
+// compareDynamic orders the keys of zero-value trees once checked by keysComparable.
+func compareDynamic[K any](k1, k2 K) int {
+	return any(k1).(constraints.Comparable[K]).Compare(k2)
+}
 
 // NewTreeFromMap creates a new red-black tree from an in-built map.
 func NewTreeFromMap[K interface {
 	constraints.Comparable[K]
 	comparable
 }, V any](m map[K]V) *Tree[K, V] {
-	tr := NewTree[K, V]()
-	for k, v := range m {
-		tr.Put(k, v)
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, compareMethod[K])
+	values := make([]V, len(keys))
+	for i, k := range keys {
+		values[i] = m[k]
+	}
+	return newTreeFromSorted(compareMethod[K], keys, values)
+}
+
+// NewTreeFromSorted creates a new red-black tree from strictly ascending keys and their values in linear time.
+func NewTreeFromSorted[K constraints.Comparable[K], V any](keys []K, values []V) (*Tree[K, V], error) {
+	return NewTreeFromSortedFunc(compareMethod[K], keys, values)
+}
+
+// NewTreeFromSortedFunc creates a new red-black tree from keys strictly ascending according to
+// a comparison function and their values in linear time.
+func NewTreeFromSortedFunc[K any, V any](compare func(K, K) int, keys []K, values []V) (*Tree[K, V], error) {
+	if len(keys) != len(values) {
+		return nil, fmt.Errorf("number of keys (%d) differs from number of values (%d)", len(keys), len(values))
+	}
+	if err := checkSorted(compare, keys); err != nil {
+		return nil, err
+	}
+	return newTreeFromSorted(compare, keys, values), nil
+}
+
+// FromSortedSeq creates a new red-black tree from a sequence of key-value pairs with strictly ascending keys.
+func FromSortedSeq[K constraints.Comparable[K], V any](seq iter.Seq2[K, V]) (*Tree[K, V], error) {
+	return FromSortedSeqFunc(compareMethod[K], seq)
+}
+
+// FromSortedSeqFunc creates a new red-black tree from a sequence of key-value pairs with keys
+// strictly ascending according to a comparison function.
+func FromSortedSeqFunc[K any, V any](compare func(K, K) int, seq iter.Seq2[K, V]) (*Tree[K, V], error) {
+	var (
+		keys   []K
+		values []V
+	)
+	for k, v := range seq {
+		if len(keys) > 0 {
+			if err := checkOrder(compare, keys[len(keys)-1], k); err != nil {
+				return nil, err
+			}
+		}
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+	return newTreeFromSorted(compare, keys, values), nil
+}
+
+func checkSorted[K any](compare func(K, K) int, keys []K) error {
+	for i := 1; i < len(keys); i++ {
+		if err := checkOrder(compare, keys[i-1], keys[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkOrder[K any](compare func(K, K) int, prev, key K) error {
+	switch c := compare(prev, key); {
+	case c == 0:
+		return fmt.Errorf("duplicate key '%v'", key)
+	case c > 0:
+		return fmt.Errorf("key '%v' out of order after '%v'", key, prev)
+	}
+	return nil
+}
+
+// newTreeFromSorted builds a tree from strictly ascending keys in linear time.
+func newTreeFromSorted[K any, V any](compare func(K, K) int, keys []K, values []V) *Tree[K, V] {
+	t := NewTreeFunc[K, V](compare)
+	t.build(keys, values)
+	return t
+}
+
+// build replaces the contents of the tree with strictly ascending keys and their values.
+// If `values` is nil, the items get zero values.
+func (t *Tree[K, V]) build(keys []K, values []V) {
+	// all the levels above the last one are complete so only the nodes on the last level are red
+	t.root = buildSorted(t, keys, values, 0, bits.Len(uint(len(keys)+1))-1)
+}
+
+// newEmpty creates an empty tree ordered, augmented and allocated like `t`.
+func (t *Tree[K, V]) newEmpty() *Tree[K, V] {
+	return &Tree[K, V]{compare: t.compare, augment: t.augment, pool: t.pool}
+}
+
+// Clone returns a copy of the tree in linear time.
+// The copy allocates its nodes on the heap even if the tree uses a node pool.
+func (t *Tree[K, V]) Clone() *Tree[K, V] {
+	// hooks aren't copied since they're usually bound to the original tree and
+	// the pool isn't shared since snapshots are cloned concurrently by readers
+	c := &Tree[K, V]{compare: t.compare, augment: t.augment}
+	if t.root != nil {
+		c.root = t.root.clone(c, nil)
 	}
-	return tr
+	return c
 }
 
 // Depth returns the depth of the tree.
@@ -30,11 +195,8 @@ func (t *Tree[K, V]) Depth() int {
 	return t.root.depth()
 }
 
-// Size returns the size of the tree.
+// Size returns the size of the tree in constant time.
 func (t *Tree[K, V]) Size() int {
-	if t.root == nil {
-		return 0
-	}
 	return t.root.size()
 }
 
@@ -46,6 +208,14 @@ func (t *Tree[K, V]) Keys() []K {
 	return t.root.keys()
 }
 
+// Values returns the values of the items in the tree ordered by their keys.
+func (t *Tree[K, V]) Values() []V {
+	if t.root == nil {
+		return nil
+	}
+	return t.root.values()
+}
+
 // Enumerate enumerates all the items in the tree.
 func (t *Tree[K, V]) Enumerate(f func(K, V) bool) bool {
 	if t.root == nil {
@@ -54,6 +224,40 @@ func (t *Tree[K, V]) Enumerate(f func(K, V) bool) bool {
 	return t.root.enumerate(f)
 }
 
+// All returns an iterator over the items in the tree in ascending key order.
+func (t *Tree[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		t.Enumerate(yield)
+	}
+}
+
+// Backward returns an iterator over the items in the tree in descending key order.
+func (t *Tree[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if t.root != nil {
+			t.root.enumerateBackward(yield)
+		}
+	}
+}
+
+// KeysSeq returns an iterator over the keys in the tree in ascending order.
+func (t *Tree[K, V]) KeysSeq() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		t.Enumerate(func(k K, _ V) bool {
+			return yield(k)
+		})
+	}
+}
+
+// EnumeratePruned enumerates the items in the tree in ascending key order, skipping every subtree
+// whose root item doesn't satisfy `enter`. It's meant for augmented trees whose values summarize their subtrees.
+func (t *Tree[K, V]) EnumeratePruned(enter, f func(K, V) bool) bool {
+	if t.root == nil {
+		return true
+	}
+	return t.root.enumeratePruned(enter, f)
+}
+
 // MinKey returns the minimum key in the tree or nil if the tree is empty.
 func (t *Tree[K, V]) MinKey() *K {
 	if t.root == nil {
@@ -62,55 +266,85 @@ func (t *Tree[K, V]) MinKey() *K {
 	return t.root.minKey()
 }
 
+// MaxKey returns the maximum key in the tree or nil if the tree is empty.
+func (t *Tree[K, V]) MaxKey() *K {
+	if t.root == nil {
+		return nil
+	}
+	return t.root.maxKey()
+}
+
+// Range enumerates the items whose keys lie between `lo` and `hi` in ascending order.
+// The flags determine whether the bounds themselves are included.
+func (t *Tree[K, V]) Range(lo, hi K, loInclusive, hiInclusive bool, f func(K, V) bool) bool {
+	if t.root == nil {
+		return true
+	}
+	return t.root.enumerateRange(t.comparator(), lo, hi, loInclusive, hiInclusive, f)
+}
+
+// Floor returns the item with the greatest key less than or equal to the given key.
+func (t *Tree[K, V]) Floor(key K) (K, V, bool) {
+	return t.root.floor(t.comparator(), key, true).item()
+}
+
+// Ceiling returns the item with the least key greater than or equal to the given key.
+func (t *Tree[K, V]) Ceiling(key K) (K, V, bool) {
+	return t.root.ceiling(t.comparator(), key, true).item()
+}
+
+// Lower returns the item with the greatest key strictly less than the given key.
+func (t *Tree[K, V]) Lower(key K) (K, V, bool) {
+	return t.root.floor(t.comparator(), key, false).item()
+}
+
+// Higher returns the item with the least key strictly greater than the given key.
+func (t *Tree[K, V]) Higher(key K) (K, V, bool) {
+	return t.root.ceiling(t.comparator(), key, false).item()
+}
+
 // Put inserts a new key-value pair into the tree or replaces the value for an existing key.
 func (t *Tree[K, V]) Put(key K, value V) (oldValue V, updated bool) {
+	compare := t.resolveComparator()
 	if t.root == nil {
-		t.root = &node[K, V]{key: key, value: value, color: black, tree: t}
+		t.root = t.newNode(key, value, black, nil)
+		t.root.update(t)
+		t.inserted(key, value)
 		return
 	}
-	n, dir := t.root.find(key)
-	switch dir {
-	case exact:
+	n, dir := t.root.find(compare, key)
+	if dir == exact {
 		oldValue = n.value
 		n.value = value
 		updated = true
+		if t.augment != nil {
+			n.updatePath(t)
+		}
+		t.updated(key, oldValue, value)
 		return
-	case left:
-		l := &node[K, V]{key: key, value: value, color: red, parent: n, tree: t}
-		n.left = l
-		l.ensureInvariants()
-	case right:
-		l := &node[K, V]{key: key, value: value, color: red, parent: n, tree: t}
-		n.right = l
-		l.ensureInvariants()
 	}
+	n.attach(t, key, value, dir)
+	t.inserted(key, value)
 	return
 }
 
 // GetElsePut returns the value for the given key or inserts a new key-value pair into the tree.
 func (t *Tree[K, V]) GetElsePut(key K, fnValue func() V) (retValue V, updated bool) {
+	compare := t.resolveComparator()
 	if t.root == nil {
 		retValue = fnValue()
-		t.root = &node[K, V]{key: key, value: retValue, color: black, tree: t}
+		t.root = t.newNode(key, retValue, black, nil)
+		t.root.update(t)
+		t.inserted(key, retValue)
 		return
 	}
-	n, dir := t.root.find(key)
-	switch dir {
-	case exact:
+	n, dir := t.root.find(compare, key)
+	if dir == exact {
 		return n.value, true
-	case left:
-		retValue = fnValue()
-		l := &node[K, V]{key: key, value: retValue, color: red, parent: n, tree: t}
-		n.left = l
-		l.ensureInvariants()
-		return
-	case right:
-		retValue = fnValue()
-		l := &node[K, V]{key: key, value: retValue, color: red, parent: n, tree: t}
-		n.right = l
-		l.ensureInvariants()
-		return
 	}
+	retValue = fnValue()
+	n.attach(t, key, retValue, dir)
+	t.inserted(key, retValue)
 	return
 }
 
@@ -119,13 +353,39 @@ func (t *Tree[K, V]) Get(key K) (retValue V, found bool) {
 	if t.root == nil {
 		return
 	}
-	n, dir := t.root.find(key)
+	n, dir := t.root.find(t.comparator(), key)
 	if dir == exact {
 		return n.value, true
 	}
 	return
 }
 
+// Rank returns the number of keys in the tree that are less than the given key.
+func (t *Tree[K, V]) Rank(key K) int {
+	return t.root.rank(t.comparator(), key)
+}
+
+// Select returns the item with the i-th smallest key (counting from zero).
+func (t *Tree[K, V]) Select(i int) (K, V, bool) {
+	return t.root.selectAt(i).item()
+}
+
+// Delete removes the key from the tree and returns its former value.
+func (t *Tree[K, V]) Delete(key K) (oldValue V, deleted bool) {
+	if t.root == nil {
+		return
+	}
+	n, dir := t.root.find(t.comparator(), key)
+	if dir != exact {
+		return
+	}
+	oldValue = n.value
+	n.remove(t)
+	t.freeNode(n)
+	t.deleted(key, oldValue)
+	return oldValue, true
+}
+
 // String returns the textual representation of the tree.
 func (t *Tree[K, V]) String() string {
 	if t.root == nil {
@@ -139,5 +399,23 @@ func (t *Tree[K, V]) Check() bool {
 	if t.root == nil {
 		return true
 	}
-	return t.root.check()
+	return t.root.check(t.comparator())
+}
+
+// CheckInvariants verifies all the invariants of the tree, namely the ordering of keys, the parent links,
+// the subtree sizes and the red-black properties (a black root, no red node with a red child
+// and the same number of black nodes on every path from the root to a leaf).
+// It returns an error describing the first violation found.
+func (t *Tree[K, V]) CheckInvariants() error {
+	if t.root == nil {
+		return nil
+	}
+	if t.root.parent != nil {
+		return fmt.Errorf("root '%v' has a parent", t.root.key)
+	}
+	if t.root.color != black {
+		return fmt.Errorf("root '%v' isn't black", t.root.key)
+	}
+	_, err := t.root.checkInvariants(t)
+	return err
 }
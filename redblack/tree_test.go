@@ -1,11 +1,15 @@
 package redblack
 
 import (
+	"cmp"
 	"fmt"
 	"math/rand"
 	"sort"
 	"strings"
 	"testing"
+
+	"github.com/fealsamh/datastructures/btree"
+	"github.com/stretchr/testify/assert"
 )
 
 type pair[T, U any] struct {
@@ -69,8 +73,368 @@ func BenchmarkRedblackTree(b *testing.B) {
 	gR = lR
 }
 
+func BenchmarkBTree(b *testing.B) {
+	pairs := generateTestData()
+	var lR interface{}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		t := btree.New[compString, int](32)
+		for _, p := range pairs {
+			t.Put(compString(p.fst), p.snd)
+		}
+		keys := make([]string, 0, t.Size())
+		t.Enumerate(func(k compString, _ int) bool {
+			keys = append(keys, string(k))
+			return true
+		})
+		lR = newPair(t, keys)
+	}
+	gR = lR
+}
+
 type compString string
 
 func (s1 compString) Compare(s2 compString) int {
 	return strings.Compare(string(s1), string(s2))
 }
+
+func TestDelete(t *testing.T) {
+	a := assert.New(t)
+
+	tr := NewTree[compString, int]()
+	m := make(map[compString]int)
+	for i := 0; i < 10_000; i++ {
+		n := rand.Intn(1_000)
+		k := compString(fmt.Sprintf("k%d", n))
+		if rand.Intn(3) == 0 {
+			v, ok := tr.Delete(k)
+			mv, mok := m[k]
+			a.Equal(mok, ok)
+			a.Equal(mv, v)
+			delete(m, k)
+		} else {
+			tr.Put(k, n)
+			m[k] = n
+		}
+		a.Equal(len(m), tr.Size())
+		a.NoError(tr.CheckInvariants())
+	}
+	for k, v := range m {
+		got, ok := tr.Get(k)
+		a.True(ok)
+		a.Equal(v, got)
+	}
+}
+
+func TestSetRemove(t *testing.T) {
+	a := assert.New(t)
+
+	s := NewSet[compString]()
+	s.Insert("a")
+	s.Insert("b")
+	a.True(s.Remove("a"))
+	a.False(s.Remove("a"))
+	a.False(s.Contains("a"))
+	a.True(s.Contains("b"))
+	a.True(s.Remove("b"))
+	a.Equal(0, s.Size())
+}
+
+func TestRange(t *testing.T) {
+	a := assert.New(t)
+
+	tr := NewTree[compString, int]()
+	for _, k := range []string{"b", "d", "f", "h"} {
+		tr.Put(compString(k), len(k))
+	}
+
+	collect := func(lo, hi compString, loInclusive, hiInclusive bool) []compString {
+		var ks []compString
+		tr.Range(lo, hi, loInclusive, hiInclusive, func(k compString, _ int) bool {
+			ks = append(ks, k)
+			return true
+		})
+		return ks
+	}
+	a.Equal([]compString{"b", "d", "f"}, collect("b", "f", true, true))
+	a.Equal([]compString{"d"}, collect("b", "f", false, false))
+	a.Equal([]compString{"d", "f"}, collect("c", "g", false, false))
+	a.Nil(collect("i", "z", true, true))
+
+	k, _, ok := tr.Floor("e")
+	a.True(ok)
+	a.Equal(compString("d"), k)
+	k, _, ok = tr.Floor("d")
+	a.True(ok)
+	a.Equal(compString("d"), k)
+	_, _, ok = tr.Floor("a")
+	a.False(ok)
+	k, _, ok = tr.Ceiling("e")
+	a.True(ok)
+	a.Equal(compString("f"), k)
+	k, _, ok = tr.Lower("d")
+	a.True(ok)
+	a.Equal(compString("b"), k)
+	k, _, ok = tr.Higher("d")
+	a.True(ok)
+	a.Equal(compString("f"), k)
+	_, _, ok = tr.Higher("h")
+	a.False(ok)
+	a.Equal(compString("h"), *tr.MaxKey())
+}
+
+func TestRankSelect(t *testing.T) {
+	a := assert.New(t)
+
+	tr := NewTree[compString, int]()
+	for i := 0; i < 2_000; i++ {
+		n := rand.Intn(500)
+		k := compString(fmt.Sprintf("k%03d", n))
+		if rand.Intn(4) == 0 {
+			tr.Delete(k)
+		} else {
+			tr.Put(k, n)
+		}
+	}
+	keys := tr.Keys()
+	a.Equal(len(keys), tr.Size())
+	for i, k := range keys {
+		a.Equal(i, tr.Rank(k))
+		sk, _, ok := tr.Select(i)
+		a.True(ok)
+		a.Equal(k, sk)
+	}
+	a.Equal(0, tr.Rank(""))
+	a.Equal(len(keys), tr.Rank("z"))
+	_, _, ok := tr.Select(-1)
+	a.False(ok)
+	_, _, ok = tr.Select(len(keys))
+	a.False(ok)
+}
+
+func TestIterators(t *testing.T) {
+	a := assert.New(t)
+
+	tr := NewTree[compString, int]()
+	for i, k := range []string{"c", "a", "d", "b"} {
+		tr.Put(compString(k), i)
+	}
+
+	var ks []compString
+	for k, v := range tr.All() {
+		got, _ := tr.Get(k)
+		a.Equal(got, v)
+		ks = append(ks, k)
+	}
+	a.Equal([]compString{"a", "b", "c", "d"}, ks)
+
+	ks = nil
+	for k := range tr.Backward() {
+		if k == "b" {
+			break
+		}
+		ks = append(ks, k)
+	}
+	a.Equal([]compString{"d", "c"}, ks)
+
+	s := NewSet[compString]()
+	for k := range tr.KeysSeq() {
+		s.Insert(k)
+	}
+	ks = nil
+	for k := range s.All() {
+		ks = append(ks, k)
+	}
+	a.Equal([]compString{"a", "b", "c", "d"}, ks)
+}
+
+func TestNewTreeFromSorted(t *testing.T) {
+	a := assert.New(t)
+
+	for size := 0; size < 300; size++ {
+		keys := make([]compString, size)
+		values := make([]int, size)
+		for i := range keys {
+			keys[i] = compString(fmt.Sprintf("k%03d", i))
+			values[i] = i
+		}
+		tr, err := NewTreeFromSorted(keys, values)
+		a.NoError(err)
+		a.NoError(tr.CheckInvariants())
+		a.Equal(size, tr.Size())
+		if size > 0 {
+			a.Equal(keys, tr.Keys())
+			v, _ := tr.Get(keys[size/3])
+			a.Equal(size/3, v)
+		}
+		tr.Put("a", -1)
+		for _, k := range keys[len(keys)/2:] {
+			tr.Delete(k)
+		}
+		a.NoError(tr.CheckInvariants())
+	}
+
+	_, err := NewTreeFromSorted([]compString{"a", "b", "b"}, []int{1, 2, 3})
+	a.Error(err)
+	_, err = NewTreeFromSorted([]compString{"b", "a"}, []int{1, 2})
+	a.Error(err)
+	_, err = NewTreeFromSorted[compString, int]([]compString{"a"}, nil)
+	a.Error(err)
+
+	src := NewTree[compString, int]()
+	for i, k := range []compString{"c", "a", "b"} {
+		src.Put(k, i)
+	}
+	tr, err := FromSortedSeq(src.All())
+	a.NoError(err)
+	a.Equal([]compString{"a", "b", "c"}, tr.Keys())
+	_, err = FromSortedSeq(src.Backward())
+	a.Error(err)
+
+	s, err := NewSetFromSorted([]compString{"a", "b"})
+	a.NoError(err)
+	a.True(s.Contains("b"))
+}
+
+func TestCheckInvariants(t *testing.T) {
+	a := assert.New(t)
+
+	tr := NewTree[compString, int]()
+	for i := 0; i < 10; i++ {
+		tr.Put(compString(fmt.Sprintf("k%d", i)), i)
+	}
+	a.NoError(tr.CheckInvariants())
+
+	tr.root.color = red
+	a.ErrorContains(tr.CheckInvariants(), "isn't black")
+	tr.root.color = black
+
+	n := tr.root.left
+	n.color = red - n.color
+	a.Error(tr.CheckInvariants())
+	n.color = red - n.color
+
+	n.left.parent = nil
+	a.ErrorContains(tr.CheckInvariants(), "doesn't point to its parent")
+	n.left.parent = n
+
+	n.count++
+	a.ErrorContains(tr.CheckInvariants(), "has size")
+	n.count--
+
+	n.key = "z"
+	a.ErrorContains(tr.CheckInvariants(), "isn't less than")
+}
+
+func BenchmarkRedblackOrderedTree(b *testing.B) {
+	pairs := generateTestData()
+	var lR interface{}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		t := NewOrderedTree[string, int]()
+		for _, p := range pairs {
+			t.Put(p.fst, p.snd)
+		}
+		keys := make([]string, 0, t.Size())
+		t.Enumerate(func(k string, _ int) bool {
+			keys = append(keys, k)
+			return true
+		})
+		lR = newPair(t, keys)
+	}
+	gR = lR
+}
+
+func TestComparatorTrees(t *testing.T) {
+	a := assert.New(t)
+
+	tr := NewOrderedTree[int, string]()
+	for _, k := range []int{5, 3, 8, 1} {
+		tr.Put(k, fmt.Sprint(k))
+	}
+	a.Equal([]int{1, 3, 5, 8}, tr.Keys())
+	k, _, ok := tr.Floor(4)
+	a.True(ok)
+	a.Equal(3, k)
+	a.NoError(tr.CheckInvariants())
+
+	desc := NewTreeFunc[string, int](func(s1, s2 string) int { return strings.Compare(s2, s1) })
+	for i, k := range []string{"a", "c", "b"} {
+		desc.Put(k, i)
+	}
+	a.Equal([]string{"c", "b", "a"}, desc.Keys())
+
+	s := NewSetFunc(cmp.Compare[float64])
+	s.Insert(2.5)
+	s.Insert(-1)
+	a.Equal([]float64{-1, 2.5}, s.Values())
+	a.True(NewOrderedSet[string]().Insert("x") == false)
+}
+
+func TestZeroValue(t *testing.T) {
+	a := assert.New(t)
+
+	var tr Tree[compString, int]
+	for i, k := range []compString{"b", "c", "a"} {
+		tr.Put(k, i)
+	}
+	a.Equal([]compString{"a", "b", "c"}, tr.Keys())
+	a.NoError(tr.CheckInvariants())
+
+	var holder struct{ s Set[compString] }
+	holder.s.Insert("y")
+	holder.s.Insert("x")
+	a.True(holder.s.Contains("x"))
+	a.Equal([]compString{"x", "y"}, holder.s.Values())
+
+	var bad Tree[int, int]
+	msg := "red-black tree of int keys must be created with a comparison function"
+	a.PanicsWithValue(msg, func() { bad.Put(1, 1) })
+	a.PanicsWithValue(msg, func() { bad.GetElsePut(1, func() int { return 1 }) })
+	a.Zero(bad.Size())
+}
+
+func newBenchmarkTree() *Tree[compString, int] {
+	t := NewTree[compString, int]()
+	for _, p := range generateTestData() {
+		t.Put(compString(p.fst), p.snd)
+	}
+	return t
+}
+
+func BenchmarkKeys(b *testing.B) {
+	t := newBenchmarkTree()
+	var lR interface{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		lR = t.Keys()
+	}
+	gR = lR
+}
+
+func BenchmarkEnumerate(b *testing.B) {
+	t := newBenchmarkTree()
+	var sum int
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		t.Enumerate(func(_ compString, v int) bool {
+			sum += v
+			return true
+		})
+	}
+	gR = sum
+}
+
+func BenchmarkDepthCheckString(b *testing.B) {
+	t := newBenchmarkTree()
+	var lR interface{}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		lR = newPair(t.Depth(), t.Check())
+		lR = t.String()
+	}
+	gR = lR
+}
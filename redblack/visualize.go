@@ -0,0 +1,127 @@
+package redblack
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Format determines how keys and values are rendered by WriteDOT and PrettyPrint.
+// A nil formatter renders using the %v verb, a formatter returning an empty string omits the item part.
+type Format[K any, V any] struct {
+	Key   func(K) string
+	Value func(V) string
+}
+
+func (f *Format[K, V]) label(key K, value V) string {
+	var ks, vs string
+	if f != nil && f.Key != nil {
+		ks = f.Key(key)
+	} else {
+		ks = fmt.Sprint(key)
+	}
+	if f != nil && f.Value != nil {
+		vs = f.Value(value)
+	} else {
+		vs = fmt.Sprint(value)
+	}
+	if vs == "" {
+		return ks
+	}
+	return ks + ":" + vs
+}
+
+// errWriter remembers the first error of a sequence of writes.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (w *errWriter) printf(format string, args ...any) {
+	if w.err == nil {
+		_, w.err = fmt.Fprintf(w.w, format, args...)
+	}
+}
+
+// WriteDOT writes the tree as a Graphviz digraph with red and black nodes and nil leaves.
+// The format may be nil.
+func (t *Tree[K, V]) WriteDOT(w io.Writer, format *Format[K, V]) error {
+	ew := &errWriter{w: w}
+	ew.printf("digraph redblack {\n")
+	ew.printf("\tnode [style=filled, fontcolor=white];\n")
+	if t.root != nil {
+		var ids, nils int
+		type frame struct {
+			n  *node[K, V]
+			id int
+		}
+		stack := []frame{{t.root, 0}}
+		for len(stack) > 0 {
+			f := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			fill := "black"
+			if f.n.color == red {
+				fill = "red"
+			}
+			ew.printf("\tn%d [label=%s, fillcolor=%s];\n", f.id, strconv.Quote(format.label(f.n.key, f.n.value)), fill)
+			for _, c := range []*node[K, V]{f.n.left, f.n.right} {
+				if c == nil {
+					ew.printf("\tnil%d [label=\"NIL\", shape=box, fillcolor=black, fontsize=8];\n", nils)
+					ew.printf("\tn%d -> nil%d;\n", f.id, nils)
+					nils++
+					continue
+				}
+				ids++
+				ew.printf("\tn%d -> n%d;\n", f.id, ids)
+				stack = append(stack, frame{c, ids})
+			}
+		}
+	}
+	ew.printf("}\n")
+	return ew.err
+}
+
+// PrettyPrint writes the tree as an indented ASCII tree with the colors of the nodes.
+// The format may be nil.
+func (t *Tree[K, V]) PrettyPrint(w io.Writer, format *Format[K, V]) error {
+	ew := &errWriter{w: w}
+	if t.root == nil {
+		ew.printf("-\n")
+		return ew.err
+	}
+	t.root.prettyPrint(ew, format, "", "")
+	return ew.err
+}
+
+func (n *node[K, V]) prettyPrint(ew *errWriter, format *Format[K, V], head, indent string) {
+	if n == nil {
+		ew.printf("%snil\n", head)
+		return
+	}
+	c := "B"
+	if n.color == red {
+		c = "R"
+	}
+	ew.printf("%s%s [%s]\n", head, format.label(n.key, n.value), c)
+	if n.left == nil && n.right == nil {
+		return
+	}
+	n.left.prettyPrint(ew, format, indent+"├─L─ ", indent+"│    ")
+	n.right.prettyPrint(ew, format, indent+"└─R─ ", indent+"     ")
+}
+
+// WriteDOT writes the set as a Graphviz digraph with red and black nodes and nil leaves.
+// The key formatter may be nil.
+func (s *Set[K]) WriteDOT(w io.Writer, key func(K) string) error {
+	return (*Tree[K, struct{}])(s).WriteDOT(w, setFormat(key))
+}
+
+// PrettyPrint writes the set as an indented ASCII tree with the colors of the nodes.
+// The key formatter may be nil.
+func (s *Set[K]) PrettyPrint(w io.Writer, key func(K) string) error {
+	return (*Tree[K, struct{}])(s).PrettyPrint(w, setFormat(key))
+}
+
+func setFormat[K any](key func(K) string) *Format[K, struct{}] {
+	return &Format[K, struct{}]{Key: key, Value: func(struct{}) string { return "" }}
+}
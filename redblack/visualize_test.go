@@ -0,0 +1,50 @@
+package redblack
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("write failed") }
+
+func TestVisualization(t *testing.T) {
+	a := assert.New(t)
+
+	tr := NewOrderedTree[int, string]()
+	for _, k := range []int{2, 1, 3, 4} {
+		tr.Put(k, strconv.Itoa(k*10))
+	}
+
+	var b strings.Builder
+	a.NoError(tr.PrettyPrint(&b, nil))
+	a.Equal(`2:20 [B]
+├─L─ 1:10 [B]
+└─R─ 3:30 [B]
+     ├─L─ nil
+     └─R─ 4:40 [R]
+`, b.String())
+
+	b.Reset()
+	a.NoError(tr.WriteDOT(&b, &Format[int, string]{Value: func(v string) string { return "v" + v }}))
+	dot := b.String()
+	a.True(strings.HasPrefix(dot, "digraph redblack {\n"))
+	a.Contains(dot, `n0 [label="2:v20", fillcolor=black];`)
+	a.Contains(dot, `fillcolor=red`)
+	a.Equal(5, strings.Count(dot, "[label=\"NIL\""))
+	a.Error(tr.WriteDOT(failingWriter{}, nil))
+
+	s := NewOrderedSet[string]()
+	s.Insert("x")
+	b.Reset()
+	a.NoError(s.PrettyPrint(&b, strings.ToUpper))
+	a.Equal("X [B]\n", b.String())
+	b.Reset()
+	a.NoError(NewOrderedSet[string]().PrettyPrint(&b, nil))
+	a.Equal("-\n", b.String())
+}
@@ -2,6 +2,7 @@ package unionfind
 
 import (
 	"fmt"
+	"iter"
 
 	"github.com/fealsamh/datastructures/constraints"
 	"github.com/fealsamh/datastructures/redblack"
@@ -10,14 +11,29 @@ import (
 
 // Structure is a union-find structure.
 type Structure[T constraints.Comparable[T]] struct {
-	values *redblack.Tree[T, *sahuaro.Tree[T]]
+	values constraints.OrderedMap[T, *sahuaro.Tree[T]]
+}
+
+// Option configures a union-find structure.
+type Option[T constraints.Comparable[T]] func(*Structure[T])
+
+// WithMap makes a union-find structure store its in-trees in the given map instead of a red-black tree.
+func WithMap[T constraints.Comparable[T]](m constraints.OrderedMap[T, *sahuaro.Tree[T]]) Option[T] {
+	return func(s *Structure[T]) {
+		s.values = m
+	}
 }
 
 // New creates a new union-find structure.
-func New[T constraints.Comparable[T]]() *Structure[T] {
-	return &Structure[T]{
-		values: redblack.NewTree[T, *sahuaro.Tree[T]](),
+func New[T constraints.Comparable[T]](opts ...Option[T]) *Structure[T] {
+	s := new(Structure[T])
+	for _, opt := range opts {
+		opt(s)
 	}
+	if s.values == nil {
+		s.values = redblack.NewTree[T, *sahuaro.Tree[T]]()
+	}
+	return s
 }
 
 // Add adds a value to a union-find structure.
@@ -43,3 +59,10 @@ func (s *Structure[T]) MustGet(val T) *sahuaro.Tree[T] {
 	}
 	return n
 }
+
+// All returns an iterator over the values and their in-trees in ascending order.
+func (s *Structure[T]) All() iter.Seq2[T, *sahuaro.Tree[T]] {
+	return func(yield func(T, *sahuaro.Tree[T]) bool) {
+		s.values.Enumerate(yield)
+	}
+}
@@ -0,0 +1,40 @@
+package unionfind
+
+import (
+	"testing"
+
+	"github.com/fealsamh/datastructures/hashmap"
+	"github.com/fealsamh/datastructures/sahuaro"
+	"github.com/stretchr/testify/assert"
+)
+
+type value int
+
+func (v1 value) Compare(v2 value) int { return int(v1) - int(v2) }
+
+func TestWithMap(t *testing.T) {
+	a := assert.New(t)
+
+	m := hashmap.New[value, *sahuaro.Tree[value]]()
+	for _, s := range []*Structure[value]{New[value](), New(WithMap[value](m))} {
+		for _, v := range []value{3, 1, 2} {
+			_, found := s.Add(v)
+			a.False(found)
+		}
+		t1, found := s.Add(1)
+		a.True(found)
+		t1.Union(s.MustGet(3))
+		a.Same(s.MustGet(1).Find(), s.MustGet(3).Find())
+		a.NotSame(s.MustGet(1).Find(), s.MustGet(2).Find())
+		_, ok := s.Get(4)
+		a.False(ok)
+		a.Panics(func() { s.MustGet(4) })
+
+		var vs []value
+		for v := range s.All() {
+			vs = append(vs, v)
+		}
+		a.Equal([]value{1, 2, 3}, vs)
+	}
+	a.Equal(3, m.Size())
+}